@@ -8,6 +8,7 @@ import (
 
 	"github.com/davidsenack/gastop/internal/adapter"
 	"github.com/davidsenack/gastop/internal/config"
+	"github.com/davidsenack/gastop/internal/graph"
 	"github.com/davidsenack/gastop/internal/tui"
 	flag "github.com/spf13/pflag"
 )
@@ -23,6 +24,8 @@ func main() {
 		rig         = flag.StringP("rig", "r", "", "Focus on a specific rig")
 		showVersion = flag.BoolP("version", "V", false, "Show version information")
 		jsonOutput  = flag.BoolP("json", "j", false, "Output JSON instead of TUI (for scripting)")
+		logFormat   = flag.String("log-format", "text", "Log format for watch: text or json")
+		logLevel    = flag.String("log-level", "info", "Log level for watch: debug, info, warn or error")
 	)
 
 	flag.Usage = func() {
@@ -30,6 +33,8 @@ func main() {
 
 Usage:
   gastop [flags]
+  gastop watch [flags]    Run headless: detect stuck work and fire alert
+                          hooks, logging to stderr, until SIGTERM
 
 Flags:
 `)
@@ -41,6 +46,7 @@ Examples:
   gastop --town ~/gt           # Specify town root (long)
   gastop -r gastown            # Focus on specific rig
   gastop -j                    # JSON output for scripting
+  gastop watch --log-format json  # Headless alerting daemon
 
 Keyboard:
   j/k     Navigate up/down
@@ -72,13 +78,30 @@ Keyboard:
 	if *townRoot != "" {
 		cfg.Paths.TownRoot = *townRoot
 	}
+	if *rig != "" {
+		cfg.Rig = *rig
+	}
 
 	// Create adapter
 	adp := adapter.New(cfg.Paths.GTBinary, cfg.Paths.BDBinary, cfg.Paths.TownRoot)
 
+	switch flag.Arg(0) {
+	case "":
+	case "watch":
+		if err := runWatch(cfg, adp, *logFormat, *logLevel); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	if *jsonOutput {
 		// JSON mode - just dump data and exit
-		runJSONMode(adp, *rig)
+		runJSONMode(adp, cfg.Rig)
 		return
 	}
 
@@ -99,6 +122,7 @@ func runJSONMode(adp *adapter.Adapter, rig string) {
 		Polecats interface{}         `json:"polecats,omitempty"`
 		Beads    interface{}         `json:"beads,omitempty"`
 		Convoys  interface{}         `json:"convoys,omitempty"`
+		Graph    *graph.Analysis     `json:"graph,omitempty"`
 		Error    string              `json:"error,omitempty"`
 	}{}
 
@@ -109,6 +133,7 @@ func runJSONMode(adp *adapter.Adapter, rig string) {
 	} else {
 		output.Status = status
 	}
+	focus := adp.ResolveRig(status, rig)
 
 	// Get polecats
 	polecats, err := adp.ListPolecats(ctx, rig)
@@ -122,7 +147,11 @@ func runJSONMode(adp *adapter.Adapter, rig string) {
 	}
 
 	// Get beads
-	beads, err := adp.ListBeads(ctx, adapter.BeadListOpts{})
+	beadOpts := adapter.BeadListOpts{}
+	if focus != nil {
+		beadOpts = focus.ScopeBeads(beadOpts)
+	}
+	beads, err := adp.ListBeads(ctx, beadOpts)
 	if err != nil {
 		if output.Error != "" {
 			output.Error += "; "
@@ -140,9 +169,22 @@ func runJSONMode(adp *adapter.Adapter, rig string) {
 		}
 		output.Error += fmt.Sprintf("failed to get convoys: %v", err)
 	} else {
+		adp.EnrichConvoyProgress(ctx, convoys, beads)
+		if focus != nil {
+			convoys = focus.FilterConvoys(convoys)
+		}
 		output.Convoys = convoys
 	}
 
+	// Analyze the dependency graph, filling in deps bd list only counted
+	if beads != nil {
+		graph.Enrich(ctx, beads, adp.GetBead, 50)
+		g := graph.Build(beads)
+		g.AddConvoys(convoys)
+		analysis := g.Analyze()
+		output.Graph = &analysis
+	}
+
 	// Marshal and print JSON
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
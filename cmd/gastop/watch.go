@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/adapter"
+	"github.com/davidsenack/gastop/internal/alert"
+	"github.com/davidsenack/gastop/internal/audit"
+	"github.com/davidsenack/gastop/internal/config"
+	"github.com/davidsenack/gastop/internal/engine"
+	"github.com/davidsenack/gastop/internal/stuck"
+)
+
+// runWatch runs the refresh loop without a TUI until SIGTERM or SIGINT,
+// firing the configured alert hooks and logging to stderr.
+func runWatch(cfg *config.Config, adp *adapter.Adapter, logFormat, logLevel string) error {
+	logger, err := newLogger(logFormat, logLevel)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	// Bell and title only make sense on an interactive terminal
+	var term alert.Terminal
+	if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		term = alert.WriterTerminal{W: os.Stdout}
+	}
+
+	eng, err := engine.New(cfg, adp, term)
+	if err != nil {
+		// Start with no snoozes rather than refusing to run
+		logger.Warn("loading state", "err", err)
+	}
+	eng.OnError = func(msg string) {
+		logger.Warn("refresh", "err", msg)
+	}
+	eng.OnAlert = func(a alert.Alert) {
+		logger.Info("alert sent", "kind", a.Kind, "entity", a.Entity, "id", a.ID,
+			"rig", a.Rig, "severity", a.Severity, "summary", a.Summary)
+	}
+	eng.OnRemediation = func(entry audit.Entry) {
+		level := slog.LevelInfo
+		if entry.Result == audit.ResultError {
+			level = slog.LevelError
+		}
+		logger.Log(context.Background(), level, "remediation", "action", entry.Action,
+			"polecat", entry.Target, "bead", entry.Bead, "policy", entry.Policy, "step", entry.Step,
+			"result", entry.Result, "err", entry.Error)
+	}
+	if !cfg.Alerts.Enabled() {
+		logger.Warn("no alert hooks configured; only logging")
+	}
+	if eng.EnableRemediation() {
+		logger.Info("remediation enabled", "dry_run", cfg.Remediation.DryRun,
+			"policies", len(cfg.Remediation.Policies), "audit_log", eng.Audit().Path())
+	}
+
+	interval := cfg.WatchInterval
+	if interval <= 0 {
+		interval = cfg.RefreshInterval
+		if interval < 10*time.Second {
+			// The TUI's 1s default is too busy for a daemon
+			if interval != config.DefaultConfig().RefreshInterval {
+				logger.Warn("refresh_interval is below 10s; watching every 10s (set watch_interval to override)",
+					"refresh_interval", interval)
+			}
+			interval = 10 * time.Second
+		}
+	}
+	townInterval := cfg.TownStatusInterval
+	if townInterval <= 0 {
+		townInterval = 30 * time.Second
+	}
+	logger.Info("watching", "town", cfg.Paths.TownRoot, "rig", cfg.Rig,
+		"interval", interval, "alerts", cfg.Alerts.Enabled())
+
+	// gt status first, so the rig focus and bead-to-rig lookups resolve
+	down := make(map[string]bool)
+	refreshTown(ctx, eng, logger, down)
+	var last stuck.StuckSummary
+	refresh(ctx, eng, logger, &last)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	townTicker := time.NewTicker(townInterval)
+	defer townTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stopping; waiting for alerts in flight")
+			eng.Wait()
+			logger.Info("stopped")
+			return nil
+		case <-ticker.C:
+			refresh(ctx, eng, logger, &last)
+		case <-townTicker.C:
+			refreshTown(ctx, eng, logger, down)
+		}
+	}
+}
+
+// refresh runs one refresh and logs the stuck counts when they change
+// from last.
+func refresh(ctx context.Context, eng *engine.Engine, logger *slog.Logger, last *stuck.StuckSummary) {
+	start := time.Now()
+	s := eng.Refresh(ctx)
+	if ctx.Err() != nil {
+		return // Shutting down; results are partial
+	}
+	logger.Debug("refreshed", "took", time.Since(start).Round(time.Millisecond),
+		"polecats", len(s.Polecats), "beads", len(s.Beads), "convoys", len(s.Convoys))
+
+	// Counts from a partial refresh would look like items clearing
+	if !s.HasPolecats || !s.HasBeads || !s.HasConvoys {
+		return
+	}
+	sum := eng.Detector().Summarize(s.Beads, s.Polecats, s.Convoys)
+	if sum != *last {
+		logger.Info("stuck items changed", "stuck", sum.Total(), "beads", sum.StuckBeads,
+			"polecats", sum.StuckPolecats, "convoys", sum.StuckConvoys,
+			"critical", sum.Critical, "snoozed", sum.Snoozed)
+		*last = sum
+	}
+}
+
+// refreshTown refreshes gt status and logs agents going down or coming
+// back, tracked in down.
+func refreshTown(ctx context.Context, eng *engine.Engine, logger *slog.Logger, down map[string]bool) {
+	agents, err := eng.RefreshTownStatus(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("gt status", "err", err)
+		}
+		return
+	}
+	for i := range agents {
+		name := agents[i].FullName()
+		isDown := agents[i].IsDown()
+		switch {
+		case isDown && !down[name]:
+			logger.Warn("agent down", "agent", name, "role", agents[i].Role)
+		case !isDown && down[name]:
+			logger.Info("agent back up", "agent", name, "role", agents[i].Role)
+		}
+		down[name] = isDown
+	}
+}
+
+// newLogger creates a text or JSON logger on stderr.
+func newLogger(format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "text", "":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (want text or json)", format)
+	}
+}
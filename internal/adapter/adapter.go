@@ -3,8 +3,10 @@ package adapter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,6 +15,7 @@ import (
 type Adapter struct {
 	gtPath   string
 	bdPath   string
+	gitPath  string
 	townRoot string
 	timeout  time.Duration
 
@@ -37,6 +40,7 @@ func New(gtPath, bdPath, townRoot string) *Adapter {
 	return &Adapter{
 		gtPath:   gtPath,
 		bdPath:   bdPath,
+		gitPath:  "git",
 		townRoot: townRoot,
 		timeout:  5 * time.Second,
 		cache:    make(map[string]*cacheEntry),
@@ -67,12 +71,18 @@ func (a *Adapter) execGT(ctx context.Context, args ...string) ([]byte, error) {
 
 // execBD runs a bd command and returns the output.
 func (a *Adapter) execBD(ctx context.Context, args ...string) ([]byte, error) {
+	return a.execBDIn(ctx, a.townRoot, args...)
+}
+
+// execBDIn runs a bd command in the given directory (e.g. a rig) so bd
+// resolves that directory's beads database.
+func (a *Adapter) execBDIn(ctx context.Context, dir string, args ...string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(ctx, a.timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, a.bdPath, args...)
-	if a.townRoot != "" {
-		cmd.Dir = a.townRoot
+	if dir != "" {
+		cmd.Dir = dir
 	}
 
 	out, err := cmd.Output()
@@ -97,6 +107,18 @@ func (a *Adapter) getCache(key string, maxAge time.Duration) (interface{}, bool)
 	return entry.data, true
 }
 
+// getFreshCache returns cached data only if it is younger than maxAge.
+func (a *Adapter) getFreshCache(key string, maxAge time.Duration) (interface{}, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entry, ok := a.cache[key]
+	if !ok || time.Since(entry.fetchedAt) > maxAge {
+		return nil, false
+	}
+	return entry.data, true
+}
+
 // setCache stores data in the cache.
 func (a *Adapter) setCache(key string, data interface{}) {
 	a.mu.Lock()
@@ -142,20 +164,72 @@ func (a *Adapter) NukePolecat(ctx context.Context, rig, name string) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	target := name
-	if rig != "" {
-		target = rig + "/" + name
+	cmd := exec.CommandContext(ctx, a.gtPath, "polecat", "nuke", polecatTarget(rig, name), "--force")
+	if a.townRoot != "" {
+		cmd.Dir = a.townRoot
 	}
 
-	cmd := exec.CommandContext(ctx, a.gtPath, "polecat", "nuke", target, "--force")
+	_, err := cmd.Output()
+	return err
+}
+
+// NudgePolecat sends a message into a polecat's session.
+func (a *Adapter) NudgePolecat(ctx context.Context, rig, name, message string) error {
+	return a.runGT(ctx, 10*time.Second, "nudge", polecatTarget(rig, name), message)
+}
+
+// RestartPolecat restarts a polecat's session, keeping its worktree and
+// hooked work.
+func (a *Adapter) RestartPolecat(ctx context.Context, rig, name string) error {
+	return a.runGT(ctx, 30*time.Second, "session", "restart", polecatTarget(rig, name))
+}
+
+// SlingBead slings a bead to a rig, which spawns a polecat to work it.
+func (a *Adapter) SlingBead(ctx context.Context, beadID, rig string) error {
+	return a.runGT(ctx, 30*time.Second, "sling", beadID, rig)
+}
+
+// SlingBeadToPolecat slings a bead onto an existing polecat's hook.
+func (a *Adapter) SlingBeadToPolecat(ctx context.Context, beadID, rig, name string) error {
+	return a.runGT(ctx, 30*time.Second, "sling", beadID, polecatTarget(rig, name))
+}
+
+// SpawnPolecat spawns a new idle polecat in a rig.
+func (a *Adapter) SpawnPolecat(ctx context.Context, rig string) error {
+	return a.runGT(ctx, 30*time.Second, "spawn", rig)
+}
+
+// runGT runs a gt command that acts on the town, with its own timeout:
+// these start sessions or create worktrees, so they outlast the read
+// timeout. A failure carries gt's stderr, as the exit status alone says
+// little.
+func (a *Adapter) runGT(ctx context.Context, timeout time.Duration, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, a.gtPath, args...)
 	if a.townRoot != "" {
 		cmd.Dir = a.townRoot
 	}
 
 	_, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+	}
 	return err
 }
 
+// polecatTarget returns the rig/name form gt takes for a polecat.
+func polecatTarget(rig, name string) string {
+	if rig == "" {
+		return name
+	}
+	return rig + "/" + name
+}
+
 // CloseBead closes a bead by ID.
 func (a *Adapter) CloseBead(ctx context.Context, beadID string) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -1,8 +1,12 @@
 package adapter
 
 import (
+	"context"
 	"encoding/json"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -743,3 +747,304 @@ func TestRigStatusParsing(t *testing.T) {
 		t.Error("expected Refinery.Running to be false")
 	}
 }
+
+// TestParseGitOutput tests parsing of git rev-list, diff and status output.
+func TestParseGitOutput(t *testing.T) {
+	behind, ahead := parseLeftRight([]byte("2\t5\n"))
+	if behind != 2 || ahead != 5 {
+		t.Errorf("parseLeftRight = %d,%d, want 2,5", behind, ahead)
+	}
+
+	tests := []struct {
+		input                       string
+		wantFiles, wantIns, wantDel int
+	}{
+		{" 3 files changed, 10 insertions(+), 2 deletions(-)\n", 3, 10, 2},
+		{" 1 file changed, 1 insertion(+)\n", 1, 1, 0},
+		{" 1 file changed, 4 deletions(-)\n", 1, 0, 4},
+		{"", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		files, ins, del := parseShortStat([]byte(tt.input))
+		if files != tt.wantFiles || ins != tt.wantIns || del != tt.wantDel {
+			t.Errorf("parseShortStat(%q) = %d,%d,%d, want %d,%d,%d",
+				tt.input, files, ins, del, tt.wantFiles, tt.wantIns, tt.wantDel)
+		}
+	}
+
+	if n := countLines([]byte(" M a.go\n?? b.go\n\n")); n != 2 {
+		t.Errorf("countLines = %d, want 2", n)
+	}
+}
+
+// TestInspectWorktree tests worktree inspection against a real git repo.
+func TestInspectWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("a.txt", "base\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "polecat/toast")
+	write("a.txt", "polecat\n")
+	write("b.txt", "new\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "polecat work")
+	git("checkout", "-q", "main")
+	write("a.txt", "main\n")
+	git("commit", "-q", "-am", "main work")
+	git("checkout", "-q", "polecat/toast")
+	write("c.txt", "dirty\n")
+
+	a := New("", "", "")
+	status, err := a.InspectWorktree(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("InspectWorktree: %v", err)
+	}
+
+	if status.Branch != "polecat/toast" {
+		t.Errorf("Branch = %s, want polecat/toast", status.Branch)
+	}
+	if status.BaseBranch != "main" {
+		t.Errorf("BaseBranch = %s, want main", status.BaseBranch)
+	}
+	if status.Ahead != 1 || status.Behind != 1 {
+		t.Errorf("Ahead/Behind = %d/%d, want 1/1", status.Ahead, status.Behind)
+	}
+	if status.Uncommitted != 1 {
+		t.Errorf("Uncommitted = %d, want 1", status.Uncommitted)
+	}
+	if status.FilesChanged != 2 {
+		t.Errorf("FilesChanged = %d, want 2", status.FilesChanged)
+	}
+	if status.LastCommit != "polecat work" {
+		t.Errorf("LastCommit = %q, want 'polecat work'", status.LastCommit)
+	}
+	if !status.HasConflicts {
+		t.Error("expected conflicts with main")
+	}
+
+	if _, err := a.InspectWorktree(context.Background(), ""); err == nil {
+		t.Error("expected error for empty clone path")
+	}
+}
+
+// TestTownStatusAgents tests flattening town status into agents.
+func TestTownStatusAgents(t *testing.T) {
+	status := TownStatus{
+		Mayor:  AgentStatus{Running: true, SessionID: "mayor-1"},
+		Deacon: AgentStatus{Running: false},
+		Rigs: []RigStatus{
+			{Name: "gastown", State: "active", Witness: AgentStatus{Running: true}},
+			{Name: "beads", State: "parked"},
+		},
+	}
+
+	agents := status.Agents()
+	if len(agents) != 6 {
+		t.Fatalf("expected 6 agents, got %d", len(agents))
+	}
+
+	byName := make(map[string]model.Agent)
+	for _, ag := range agents {
+		byName[ag.FullName()] = ag
+	}
+
+	if ag := byName["mayor"]; !ag.Running || ag.SessionID != "mayor-1" || ag.IsDown() {
+		t.Errorf("unexpected mayor: %+v", ag)
+	}
+	if ag := byName["deacon"]; !ag.IsDown() {
+		t.Error("expected deacon to be down")
+	}
+	if ag := byName["gastown/refinery"]; !ag.IsDown() {
+		t.Error("expected refinery of active rig to be down")
+	}
+	if ag := byName["beads/witness"]; ag.Required || ag.IsDown() {
+		t.Error("expected witness of parked rig to not be required")
+	}
+}
+
+// TestRigScoping tests rig lookup, prefix matching and convoy filtering.
+func TestRigScoping(t *testing.T) {
+	if !HasPrefix("gt-abc", "gt") || !HasPrefix("gt-abc", "gt-") {
+		t.Error("expected gt-abc to match prefix gt")
+	}
+	if HasPrefix("gtx-abc", "gt") || HasPrefix("gt-abc", "") {
+		t.Error("unexpected prefix match")
+	}
+
+	beads := filterBeadsByPrefix([]model.Bead{{ID: "gt-1"}, {ID: "bd-2"}, {ID: "gt-3"}}, "gt")
+	if len(beads) != 2 || beads[1].ID != "gt-3" {
+		t.Errorf("filterBeadsByPrefix = %v, want gt-1, gt-3", beads)
+	}
+
+	a := New("", "", "/town")
+	status := &TownStatus{Rigs: []RigStatus{{Name: "gastown", Path: "/town/gastown", Prefix: "gt", State: "parked"}}}
+
+	rig := a.ResolveRig(status, "gastown")
+	if rig == nil || rig.State != "parked" {
+		t.Fatalf("ResolveRig(gastown) = %+v", rig)
+	}
+	opts := rig.ScopeBeads(BeadListOpts{Limit: 10})
+	if opts.Dir != "/town/gastown" || opts.Prefix != "gt" || opts.Limit != 10 {
+		t.Errorf("ScopeBeads = %+v", opts)
+	}
+
+	if fallback := a.ResolveRig(nil, "beads"); fallback == nil || fallback.Path != "/town/beads" {
+		t.Errorf("ResolveRig fallback = %+v, want path /town/beads", fallback)
+	}
+	if a.ResolveRig(status, "") != nil {
+		t.Error("expected nil rig for empty name")
+	}
+
+	convoys := []model.Convoy{
+		{ID: "hq-1", TrackedIDs: []string{"bd-1", "gt-2"}},
+		{ID: "hq-2", TrackedIDs: []string{"bd-3"}},
+		{ID: "gt-cv"},
+	}
+	filtered := rig.FilterConvoys(convoys)
+	if len(filtered) != 2 || filtered[0].ID != "hq-1" || filtered[1].ID != "gt-cv" {
+		t.Errorf("FilterConvoys = %v, want hq-1, gt-cv", filtered)
+	}
+}
+
+func TestEnrichConvoyProgress(t *testing.T) {
+	a := New("/nonexistent/gt", "/nonexistent/bd", t.TempDir())
+
+	// A bead from another rig, already fetched by bd show
+	a.setCache("bead:bd-003", &model.Bead{ID: "bd-003", Status: "in_progress"})
+
+	known := []model.Bead{
+		{ID: "gt-001", Status: "closed"},
+		{ID: "gt-002", Status: "blocked"},
+	}
+	convoys := []model.Convoy{
+		// Reported counts are stale and get replaced
+		{ID: "hq-cv-1", TrackedIDs: []string{"gt-001", "gt-002", "bd-003", "gt-404"}, TotalCount: 4},
+		// Nothing resolvable: reported counts are kept
+		{ID: "hq-cv-2", TrackedIDs: []string{"gt-404"}, TotalCount: 1, ClosedCount: 1},
+	}
+
+	extra := a.EnrichConvoyProgress(context.Background(), convoys, known)
+	if len(extra) != 1 || extra[0].ID != "bd-003" {
+		t.Errorf("extra = %v, want just bd-003", extra)
+	}
+
+	c := convoys[0]
+	if c.Counts == nil {
+		t.Fatal("expected counts derived from tracked beads")
+	}
+	want := model.ConvoyCounts{Closed: 1, Blocked: 1, InProgress: 1, Unknown: 1}
+	if *c.Counts != want {
+		t.Errorf("counts = %+v, want %+v", *c.Counts, want)
+	}
+	if c.ProgressString() != "1/4" {
+		t.Errorf("progress = %s, want 1/4", c.ProgressString())
+	}
+
+	if convoys[1].Counts != nil || convoys[1].ProgressString() != "1/1" {
+		t.Errorf("expected unresolved convoy to keep its counts, got %s", convoys[1].ProgressString())
+	}
+}
+
+// TestActionCommands tests the gt commands run by the polecat actions.
+func TestActionCommands(t *testing.T) {
+	dir := t.TempDir()
+	gt := filepath.Join(dir, "gt")
+	script := "#!/bin/sh\necho \"$*\" >> \"" + dir + "/calls\"\n"
+	if err := os.WriteFile(gt, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	a := New(gt, "", dir)
+	ctx := context.Background()
+
+	if err := a.NudgePolecat(ctx, "gastown", "Toast", "keep going"); err != nil {
+		t.Fatalf("NudgePolecat: %v", err)
+	}
+	if err := a.SpawnPolecat(ctx, "gastown"); err != nil {
+		t.Fatalf("SpawnPolecat: %v", err)
+	}
+	if err := a.SlingBead(ctx, "gt-1", "gastown"); err != nil {
+		t.Fatalf("SlingBead: %v", err)
+	}
+	if err := a.SlingBeadToPolecat(ctx, "gt-2", "gastown", "Nux"); err != nil {
+		t.Fatalf("SlingBeadToPolecat: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "nudge gastown/Toast keep going\nspawn gastown\nsling gt-1 gastown\nsling gt-2 gastown/Nux\n"
+	if string(data) != want {
+		t.Errorf("gt calls = %q, want %q", data, want)
+	}
+
+	if err := New(filepath.Join(dir, "missing"), "", dir).SpawnPolecat(ctx, "gastown"); err == nil {
+		t.Error("expected an error when gt is missing")
+	}
+
+	// Failures carry gt's stderr
+	failing := filepath.Join(dir, "gt-fail")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho 'rig not found: nowhere' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = New(failing, "", dir).SlingBead(ctx, "gt-1", "nowhere")
+	if err == nil || !strings.Contains(err.Error(), "rig not found: nowhere") {
+		t.Errorf("expected gt's stderr in the error, got %v", err)
+	}
+}
+
+func TestEnrichMoleculesCache(t *testing.T) {
+	dir := t.TempDir()
+	bd := filepath.Join(dir, "bd")
+	script := "#!/bin/sh\necho \"$*\" >> \"" + dir + "/calls\"\necho '{\"id\":\"'$3'\",\"title\":\"wf\",\"steps\":[]}'\n"
+	if err := os.WriteFile(bd, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	a := New("", bd, dir)
+	ctx := context.Background()
+
+	beads := []model.Bead{
+		{ID: "gt-m1", IssueType: model.TypeMolecule, Status: model.StatusOpen},
+		{ID: "gt-m2", IssueType: model.TypeMolecule, Status: model.StatusInProgress},
+		{ID: "gt-m3", IssueType: model.TypeMolecule, Status: model.StatusClosed},
+		{ID: "gt-1", Status: model.StatusOpen},
+	}
+	for i := 0; i < 3; i++ {
+		a.EnrichMolecules(ctx, beads, nil)
+	}
+
+	if beads[0].Molecule == nil || beads[0].Molecule.ID != "gt-m1" || beads[1].Molecule == nil {
+		t.Fatalf("expected open molecules to be enriched, got %+v, %+v", beads[0].Molecule, beads[1].Molecule)
+	}
+	if beads[2].Molecule != nil || beads[3].Molecule != nil {
+		t.Error("expected closed molecules and plain beads to be skipped")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := strings.Count(string(data), "mol show"); calls != 2 {
+		t.Errorf("expected one bd mol show per molecule across refreshes, got %d:\n%s", calls, data)
+	}
+}
@@ -3,6 +3,7 @@ package adapter
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/davidsenack/gastop/internal/model"
@@ -16,6 +17,8 @@ type BeadListOpts struct {
 	Type     string // Filter by type
 	Ready    bool   // Only ready work
 	Blocked  bool   // Only blocked work
+	Dir      string // Run bd in this directory (e.g. a rig path)
+	Prefix   string // Only beads with this ID prefix (e.g. a rig prefix)
 }
 
 // ListBeads returns beads matching the options.
@@ -52,7 +55,16 @@ func (a *Adapter) ListBeads(ctx context.Context, opts BeadListOpts) ([]model.Bea
 		cacheKey += ":type=" + opts.Type
 	}
 
-	out, err := a.execBD(ctx, args...)
+	dir := a.townRoot
+	if opts.Dir != "" {
+		dir = opts.Dir
+		cacheKey += ":dir=" + opts.Dir
+	}
+	if opts.Prefix != "" {
+		cacheKey += ":prefix=" + opts.Prefix
+	}
+
+	out, err := a.execBDIn(ctx, dir, args...)
 	if err != nil {
 		if cached, ok := a.getCache(cacheKey, 5*time.Minute); ok {
 			beads := cached.([]model.Bead)
@@ -69,6 +81,9 @@ func (a *Adapter) ListBeads(ctx context.Context, opts BeadListOpts) ([]model.Bea
 	if err := parseJSON(out, &beads); err != nil {
 		return nil, err
 	}
+	if opts.Prefix != "" {
+		beads = filterBeadsByPrefix(beads, opts.Prefix)
+	}
 
 	// Compute age for each bead
 	for i := range beads {
@@ -104,6 +119,24 @@ func (a *Adapter) GetBead(ctx context.Context, id string) (*model.Bead, error) {
 	return &bead, nil
 }
 
+// HasPrefix returns true if a bead ID carries the given prefix,
+// which may be given with or without its trailing dash.
+func HasPrefix(id, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "-")
+	return prefix != "" && strings.HasPrefix(id, prefix+"-")
+}
+
+// filterBeadsByPrefix keeps only beads whose ID has the prefix.
+func filterBeadsByPrefix(beads []model.Bead, prefix string) []model.Bead {
+	filtered := beads[:0]
+	for _, b := range beads {
+		if HasPrefix(b.ID, prefix) {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
+
 // ListReadyBeads returns beads ready for work (no blockers).
 func (a *Adapter) ListReadyBeads(ctx context.Context, limit int) ([]model.Bead, error) {
 	return a.ListBeads(ctx, BeadListOpts{Ready: true, Limit: limit})
@@ -113,3 +146,55 @@ func (a *Adapter) ListReadyBeads(ctx context.Context, limit int) ([]model.Bead,
 func (a *Adapter) ListBlockedBeads(ctx context.Context) ([]model.Bead, error) {
 	return a.ListBeads(ctx, BeadListOpts{Blocked: true})
 }
+
+// maxBlockerLookups caps the bd show calls made per EnrichBlockers.
+const maxBlockerLookups = 50
+
+// EnrichBlockers fills in each bead's Blockers with the current status of
+// the beads blocking it. Blockers in known (the current bead list) are used
+// as-is; others are fetched with bd show and cached briefly. Beads that
+// report blockers only as a count get their IDs from bd show as well.
+func (a *Adapter) EnrichBlockers(ctx context.Context, beads []model.Bead, known []model.Bead) {
+	byID := make(map[string]model.Bead, len(known))
+	for _, b := range known {
+		byID[b.ID] = b
+	}
+
+	lookups := 0
+	fetch := func(id string) (*model.Bead, bool) {
+		if cached, ok := a.getFreshCache("bead:"+id, 30*time.Second); ok {
+			return cached.(*model.Bead), true
+		}
+		if lookups >= maxBlockerLookups {
+			return nil, false
+		}
+		lookups++
+		b, err := a.GetBead(ctx, id)
+		return b, err == nil
+	}
+
+	for i := range beads {
+		b := &beads[i]
+		if len(b.BlockedBy) == 0 && b.BlockerCount > 0 {
+			if full, ok := fetch(b.ID); ok {
+				b.BlockedBy = full.BlockedBy
+			}
+		}
+
+		b.Blockers = make([]model.Blocker, 0, len(b.BlockedBy))
+		for _, id := range b.BlockedBy {
+			blocker := model.Blocker{ID: id}
+			dep, ok := byID[id]
+			if !ok {
+				if fetched, found := fetch(id); found {
+					dep, ok = *fetched, true
+					byID[id] = dep
+				}
+			}
+			if ok {
+				blocker.Title, blocker.Status, blocker.Assignee = dep.Title, dep.Status, dep.Assignee
+			}
+			b.Blockers = append(b.Blockers, blocker)
+		}
+	}
+}
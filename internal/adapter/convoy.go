@@ -62,6 +62,9 @@ func (a *Adapter) ListConvoys(ctx context.Context, opts ConvoyListOpts) ([]model
 		return nil, err
 	}
 
+	// Convoys can appear in both listings
+	convoys = model.DedupeConvoys(convoys)
+
 	// Compute progress for each convoy
 	for i := range convoys {
 		convoys[i].ComputeProgress()
@@ -95,3 +98,56 @@ func (a *Adapter) GetConvoyStatus(ctx context.Context, id string) (*model.Convoy
 	a.setCache(cacheKey, &convoy)
 	return &convoy, nil
 }
+
+// maxTrackedLookups caps the bd show calls made per EnrichConvoyProgress.
+const maxTrackedLookups = 50
+
+// EnrichConvoyProgress recomputes convoy progress from the live state of
+// tracked beads. Beads in known (the current bead list) are used as-is;
+// others, including beads in other rigs, are fetched with bd show and
+// cached briefly. bd-listed convoys that only report a dependency count
+// get their tracked IDs from bd show as well. The tracked beads that were
+// not in known are returned, so callers can check them too.
+func (a *Adapter) EnrichConvoyProgress(ctx context.Context, convoys []model.Convoy, known []model.Bead) []model.Bead {
+	byID := make(map[string]model.Bead, len(known))
+	for _, b := range known {
+		byID[b.ID] = b
+	}
+
+	lookups := 0
+	fetch := func(id string) (*model.Bead, bool) {
+		if cached, ok := a.getFreshCache("bead:"+id, 30*time.Second); ok {
+			return cached.(*model.Bead), true
+		}
+		if lookups >= maxTrackedLookups {
+			return nil, false
+		}
+		lookups++
+		b, err := a.GetBead(ctx, id)
+		return b, err == nil
+	}
+
+	var extra []model.Bead
+	lookup := func(id string) (model.Bead, bool) {
+		if b, ok := byID[id]; ok {
+			return b, true
+		}
+		if b, ok := fetch(id); ok {
+			byID[id] = *b
+			extra = append(extra, *b)
+			return *b, true
+		}
+		return model.Bead{}, false
+	}
+
+	for i := range convoys {
+		c := &convoys[i]
+		if len(c.TrackedIDs) == 0 && c.DependencyCount > 0 {
+			if full, ok := fetch(c.ID); ok {
+				c.TrackedIDs = full.BlockedBy
+			}
+		}
+		c.ComputeFromTracked(lookup)
+	}
+	return extra
+}
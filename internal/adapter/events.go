@@ -166,3 +166,110 @@ type AgentStatus struct {
 	Running   bool   `json:"running"`
 	SessionID string `json:"session_id,omitempty"`
 }
+
+// FindRig returns the rig with the given name, or nil if unknown.
+func (s *TownStatus) FindRig(name string) *RigStatus {
+	for i := range s.Rigs {
+		if s.Rigs[i].Name == name {
+			return &s.Rigs[i]
+		}
+	}
+	return nil
+}
+
+// RigOf returns the name of the rig owning a bead or convoy ID, or "".
+func (s *TownStatus) RigOf(id string) string {
+	for i := range s.Rigs {
+		if s.Rigs[i].Owns(id) {
+			return s.Rigs[i].Name
+		}
+	}
+	return ""
+}
+
+// Owns returns true if a bead or convoy ID belongs to the rig.
+func (r *RigStatus) Owns(id string) bool {
+	return HasPrefix(id, r.Prefix)
+}
+
+// ScopeBeads restricts bead list options to the rig's directory and prefix.
+func (r *RigStatus) ScopeBeads(opts BeadListOpts) BeadListOpts {
+	opts.Dir = r.Path
+	opts.Prefix = r.Prefix
+	return opts
+}
+
+// FilterConvoys keeps convoys that belong to the rig or track its beads.
+// Without a known prefix there is nothing to match on, so all are kept.
+func (r *RigStatus) FilterConvoys(convoys []model.Convoy) []model.Convoy {
+	if r.Prefix == "" {
+		return convoys
+	}
+	var filtered []model.Convoy
+	for _, c := range convoys {
+		if r.Owns(c.ID) {
+			filtered = append(filtered, c)
+			continue
+		}
+		for _, id := range c.TrackedIDs {
+			if r.Owns(id) {
+				filtered = append(filtered, c)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// ResolveRig looks up a rig by name in the town status. Before gt status has
+// answered (or for unregistered rigs) it falls back to <town>/<rig>.
+func (a *Adapter) ResolveRig(status *TownStatus, name string) *RigStatus {
+	if name == "" {
+		return nil
+	}
+	if status != nil {
+		if rig := status.FindRig(name); rig != nil {
+			return rig
+		}
+	}
+	rig := &RigStatus{Name: name}
+	if a.townRoot != "" {
+		rig.Path = filepath.Join(a.townRoot, name)
+	}
+	return rig
+}
+
+// Agents flattens the town's infrastructure agents: mayor, deacon, and each
+// rig's witness and refinery. Rig agents are only required while the rig
+// is active.
+func (s *TownStatus) Agents() []model.Agent {
+	agents := []model.Agent{
+		townAgent("mayor", "", s.Mayor, true),
+		townAgent("deacon", "", s.Deacon, true),
+	}
+	for _, rig := range s.Rigs {
+		required := rig.State == "" || rig.State == "active"
+		agents = append(agents,
+			townAgent("witness", rig.Name, rig.Witness, required),
+			townAgent("refinery", rig.Name, rig.Refinery, required),
+		)
+	}
+	return agents
+}
+
+// townAgent converts an AgentStatus into a model.Agent.
+func townAgent(role, rig string, status AgentStatus, required bool) model.Agent {
+	state := "stopped"
+	if status.Running {
+		state = "running"
+	}
+	return model.Agent{
+		Name:      role,
+		Role:      role,
+		Rig:       rig,
+		State:     state,
+		Running:   status.Running,
+		SessionID: status.SessionID,
+		Required:  required,
+	}
+}
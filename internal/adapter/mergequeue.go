@@ -0,0 +1,42 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// ListMergeQueue returns the entries in a rig's merge queue (or all rigs if
+// rig is empty).
+func (a *Adapter) ListMergeQueue(ctx context.Context, rig string) ([]model.MergeRequest, error) {
+	cacheKey := "mq"
+	args := []string{"mq", "list", "--json"}
+	if rig != "" {
+		args = append(args, rig)
+		cacheKey += ":" + rig
+	}
+
+	out, err := a.execGT(ctx, args...)
+	if err != nil {
+		if cached, ok := a.getCache(cacheKey, 5*time.Minute); ok {
+			return cached.([]model.MergeRequest), nil
+		}
+		return nil, err
+	}
+
+	var requests []model.MergeRequest
+	if err := parseJSON(out, &requests); err != nil {
+		return nil, err
+	}
+
+	// Entries listed for a single rig may omit the rig name
+	for i := range requests {
+		if requests[i].Rig == "" {
+			requests[i].Rig = rig
+		}
+	}
+
+	a.setCache(cacheKey, requests)
+	return requests, nil
+}
@@ -0,0 +1,81 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// GetMolecule returns a molecule with its ordered workflow steps.
+func (a *Adapter) GetMolecule(ctx context.Context, id string) (*model.Molecule, error) {
+	cacheKey := "mol:" + id
+
+	out, err := a.execBD(ctx, "mol", "show", id, "--json")
+	if err != nil {
+		if cached, ok := a.getCache(cacheKey, 5*time.Minute); ok {
+			return cached.(*model.Molecule), nil
+		}
+		return nil, err
+	}
+
+	var mol model.Molecule
+	if err := parseJSON(out, &mol); err != nil {
+		return nil, err
+	}
+	if mol.ID == "" {
+		mol.ID = id
+	}
+	for i := range mol.Steps {
+		mol.Steps[i].ComputeAge()
+	}
+
+	a.setCache(cacheKey, &mol)
+	return &mol, nil
+}
+
+// moleculeTTL is how long EnrichMolecules reuses a molecule before
+// fetching it again; steps change far slower than the refresh interval.
+const moleculeTTL = 30 * time.Second
+
+// maxMoleculeFetches caps the bd mol show calls EnrichMolecules runs at
+// once.
+const maxMoleculeFetches = 4
+
+// EnrichMolecules attaches workflow steps to open molecule beads and
+// resolves which polecat holds each molecule's current step. Molecules
+// fetched within moleculeTTL are reused; the rest are fetched in parallel.
+func (a *Adapter) EnrichMolecules(ctx context.Context, beads []model.Bead, polecats []model.Polecat) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxMoleculeFetches)
+	for i := range beads {
+		b := &beads[i]
+		if !b.IsMolecule() || b.Status.IsDone() {
+			continue
+		}
+		if cached, ok := a.getFreshCache("mol:"+b.ID, moleculeTTL); ok {
+			attachMolecule(b, cached.(*model.Molecule), polecats)
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if mol, err := a.GetMolecule(ctx, b.ID); err == nil {
+				attachMolecule(b, mol, polecats)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// attachMolecule attaches a copy of a molecule to its bead, so attaching
+// the holder doesn't touch the cached molecule.
+func attachMolecule(b *model.Bead, mol *model.Molecule, polecats []model.Polecat) {
+	m := *mol
+	m.AttachHolder(polecats)
+	b.Molecule = &m
+}
@@ -117,7 +117,7 @@ func (a *Adapter) EnrichPolecatsWithHooks(ctx context.Context, polecats []model.
 	for i := range polecats {
 		pc := &polecats[i]
 		// Only fetch hooks for working polecats to minimize API calls
-		if pc.State == "working" || pc.State == "done" {
+		if pc.State.Is(model.PolecatWorking) || pc.State.Is(model.PolecatDone) {
 			status, err := a.GetHookedBead(ctx, pc.FullName())
 			if err == nil && status.Status == "hooked" {
 				pc.HookedBead = status.Bead
@@ -0,0 +1,147 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// execGit runs a git command in the given directory and returns the output.
+func (a *Adapter) execGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, a.gitPath, args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("command timed out: git %v", args)
+	}
+	return out, err
+}
+
+// InspectWorktree runs git in a polecat's clone and summarizes its progress
+// against the rig's default branch.
+func (a *Adapter) InspectWorktree(ctx context.Context, clonePath string) (*model.WorktreeStatus, error) {
+	if clonePath == "" {
+		return nil, errors.New("polecat has no clone path")
+	}
+	cacheKey := "worktree:" + clonePath
+
+	branch, err := a.execGit(ctx, clonePath, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		if cached, ok := a.getCache(cacheKey, 5*time.Minute); ok {
+			return cached.(*model.WorktreeStatus), nil
+		}
+		return nil, err
+	}
+
+	status := &model.WorktreeStatus{
+		Path:       clonePath,
+		Branch:     strings.TrimSpace(string(branch)),
+		BaseBranch: a.defaultBranch(ctx, clonePath),
+	}
+
+	if out, err := a.execGit(ctx, clonePath, "status", "--porcelain"); err == nil {
+		status.Uncommitted = countLines(out)
+	}
+
+	if out, err := a.execGit(ctx, clonePath, "log", "-1", "--format=%s%x1f%cI"); err == nil {
+		if msg, ts, ok := strings.Cut(strings.TrimSpace(string(out)), "\x1f"); ok {
+			status.LastCommit = msg
+			status.LastCommitAt, _ = time.Parse(time.RFC3339, ts)
+		}
+	}
+
+	// Everything below compares against the default branch
+	if status.BaseBranch == "" {
+		a.setCache(cacheKey, status)
+		return status, nil
+	}
+
+	rangeSpec := status.BaseBranch + "...HEAD"
+	if out, err := a.execGit(ctx, clonePath, "rev-list", "--left-right", "--count", rangeSpec); err == nil {
+		status.Behind, status.Ahead = parseLeftRight(out)
+	}
+	if out, err := a.execGit(ctx, clonePath, "diff", "--shortstat", rangeSpec); err == nil {
+		status.FilesChanged, status.Insertions, status.Deletions = parseShortStat(out)
+	}
+
+	// merge-tree exits 1 when the merge would conflict (git 2.38+)
+	_, err = a.execGit(ctx, clonePath, "merge-tree", "--write-tree", "--name-only", status.BaseBranch, "HEAD")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		status.HasConflicts = true
+	}
+
+	a.setCache(cacheKey, status)
+	return status, nil
+}
+
+// defaultBranch returns the rig's default branch as seen from a clone,
+// preferring the remote's HEAD and falling back to common names.
+func (a *Adapter) defaultBranch(ctx context.Context, dir string) string {
+	if out, err := a.execGit(ctx, dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if ref := strings.TrimSpace(string(out)); ref != "" {
+			return ref
+		}
+	}
+	for _, ref := range []string{"origin/main", "origin/master", "main", "master"} {
+		if _, err := a.execGit(ctx, dir, "rev-parse", "--verify", "--quiet", ref); err == nil {
+			return ref
+		}
+	}
+	return ""
+}
+
+// parseLeftRight parses `git rev-list --left-right --count` output.
+func parseLeftRight(out []byte) (left, right int) {
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0
+	}
+	left, _ = strconv.Atoi(fields[0])
+	right, _ = strconv.Atoi(fields[1])
+	return left, right
+}
+
+var shortStatRe = regexp.MustCompile(`(\d+) (file|insertion|deletion)`)
+
+// parseShortStat parses `git diff --shortstat` output, e.g.
+// " 3 files changed, 10 insertions(+), 2 deletions(-)".
+func parseShortStat(out []byte) (files, insertions, deletions int) {
+	for _, m := range shortStatRe.FindAllStringSubmatch(string(out), -1) {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "file":
+			files = n
+		case "insertion":
+			insertions = n
+		case "deletion":
+			deletions = n
+		}
+	}
+	return files, insertions, deletions
+}
+
+// countLines returns the number of non-empty lines in the output.
+func countLines(out []byte) int {
+	n := 0
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			n++
+		}
+	}
+	return n
+}
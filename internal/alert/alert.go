@@ -0,0 +1,211 @@
+// Package alert notifies people outside the TUI when something needs
+// attention: an item becoming stuck, a polecat crash, a failing merge or
+// an infrastructure agent going down.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Alert kinds.
+const (
+	KindStuck       = "stuck"
+	KindCrash       = "crash"
+	KindMergeFailed = "merge_failed"
+	KindAgentDown   = "agent_down"
+)
+
+// Alert is one notification, delivered as JSON to commands and webhooks.
+type Alert struct {
+	Kind     string    `json:"kind"`
+	Entity   string    `json:"entity"` // bead, polecat, convoy, merge, agent
+	ID       string    `json:"id"`
+	Rig      string    `json:"rig,omitempty"`
+	Severity string    `json:"severity"` // warning, critical
+	Summary  string    `json:"summary"`
+	Time     time.Time `json:"time"`
+}
+
+// Key identifies an alert for deduplication. The severity is part of it,
+// so an item escalating to critical alerts again within the dedup window.
+func (a Alert) Key() string {
+	return a.Kind + ":" + a.Entity + ":" + a.ID + ":" + a.Severity
+}
+
+// String returns a one-line description.
+func (a Alert) String() string {
+	return fmt.Sprintf("%s %s: %s", a.Entity, a.ID, a.Summary)
+}
+
+// Config configures alert delivery. It is read from the [alerts] table of
+// the config file. Nothing is delivered unless at least one of Command,
+// Webhook, Bell or Title is set.
+type Config struct {
+	// Command is run with sh -c for each alert, with the alert as JSON on
+	// stdin and in GASTOP_ALERT_* environment variables.
+	Command string `toml:"command"`
+	// Webhook receives each alert as a JSON POST.
+	Webhook string `toml:"webhook"`
+	// Bell rings the terminal bell; Title shows the alert in the terminal
+	// title.
+	Bell  bool `toml:"bell"`
+	Title bool `toml:"title"`
+
+	// Kinds limits alerts to these kinds (default: all).
+	Kinds []string `toml:"kinds"`
+	// Dedup suppresses repeats of the same alert for the same item within
+	// this window (default 15m).
+	Dedup time.Duration `toml:"dedup"`
+	// RateLimit caps alerts delivered per minute across all items
+	// (default 10).
+	RateLimit int `toml:"rate_limit"`
+	// Timeout bounds each command or webhook call (default 10s).
+	Timeout time.Duration `toml:"timeout"`
+}
+
+// Enabled reports whether any delivery is configured.
+func (c Config) Enabled() bool {
+	return c.Command != "" || c.Webhook != "" || c.Bell || c.Title
+}
+
+// withDefaults fills unset values.
+func (c Config) withDefaults() Config {
+	if c.Dedup <= 0 {
+		c.Dedup = 15 * time.Minute
+	}
+	if c.RateLimit <= 0 {
+		c.RateLimit = 10
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// Notifier delivers an alert somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// Dispatcher filters, deduplicates and rate-limits alerts and hands the
+// rest to its notifiers.
+type Dispatcher struct {
+	cfg       Config
+	notifiers []Notifier
+	now       func() time.Time
+
+	// OnError is called with delivery failures. Optional.
+	OnError func(error)
+
+	mu      sync.Mutex
+	sent    map[string]time.Time // alert key -> last delivery
+	recent  []time.Time          // deliveries within the last minute
+	dropped int                  // alerts dropped by the rate limit
+}
+
+// NewDispatcher creates a dispatcher for the configured command and
+// webhook, plus term for the bell and title if given.
+func NewDispatcher(cfg Config, term Terminal) *Dispatcher {
+	cfg = cfg.withDefaults()
+	d := &Dispatcher{cfg: cfg, now: time.Now, sent: make(map[string]time.Time)}
+	if cfg.Command != "" {
+		d.notifiers = append(d.notifiers, &CommandNotifier{Command: cfg.Command})
+	}
+	if cfg.Webhook != "" {
+		d.notifiers = append(d.notifiers, &WebhookNotifier{URL: cfg.Webhook})
+	}
+	if term != nil && (cfg.Bell || cfg.Title) {
+		d.notifiers = append(d.notifiers, &TerminalNotifier{Term: term, Bell: cfg.Bell, Title: cfg.Title})
+	}
+	return d
+}
+
+// AddNotifier adds a notifier, for custom delivery and tests.
+func (d *Dispatcher) AddNotifier(n Notifier) {
+	d.notifiers = append(d.notifiers, n)
+}
+
+// Fire delivers the alerts that pass the kind filter, dedup window and
+// rate limit, and returns them. Delivery is synchronous; notifiers are
+// called in order for each alert, each bounded by the timeout.
+func (d *Dispatcher) Fire(ctx context.Context, alerts []Alert) []Alert {
+	deliver := d.admit(alerts)
+	for _, a := range deliver {
+		for _, n := range d.notifiers {
+			nctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout)
+			err := n.Notify(nctx, a)
+			cancel()
+			if err != nil && d.OnError != nil {
+				d.OnError(fmt.Errorf("alert %s: %w", a.Key(), err))
+			}
+		}
+	}
+	return deliver
+}
+
+// admit applies the kind filter, dedup and rate limit.
+func (d *Dispatcher) admit(alerts []Alert) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+
+	// Forget deliveries outside the windows
+	for key, t := range d.sent {
+		if now.Sub(t) >= d.cfg.Dedup {
+			delete(d.sent, key)
+		}
+	}
+	for len(d.recent) > 0 && now.Sub(d.recent[0]) >= time.Minute {
+		d.recent = d.recent[1:]
+	}
+
+	var out []Alert
+	dropped := 0
+	for _, a := range alerts {
+		if !d.wants(a.Kind) {
+			continue
+		}
+		if _, dup := d.sent[a.Key()]; dup {
+			continue
+		}
+		if len(d.recent) >= d.cfg.RateLimit {
+			dropped++
+			continue
+		}
+		if a.Time.IsZero() {
+			a.Time = now
+		}
+		d.sent[a.Key()] = now
+		d.recent = append(d.recent, now)
+		out = append(out, a)
+	}
+
+	d.dropped += dropped
+	if dropped > 0 && d.OnError != nil {
+		d.OnError(fmt.Errorf("rate limit dropped %d alert(s)", dropped))
+	}
+	return out
+}
+
+// wants reports whether alerts of kind are enabled.
+func (d *Dispatcher) wants(kind string) bool {
+	if len(d.cfg.Kinds) == 0 {
+		return true
+	}
+	for _, k := range d.cfg.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Dropped returns the number of alerts dropped by the rate limit.
+func (d *Dispatcher) Dropped() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dropped
+}
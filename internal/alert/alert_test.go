@@ -0,0 +1,366 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/stuck"
+)
+
+// recorder is a Notifier that records what it was sent.
+type recorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *recorder) Notify(ctx context.Context, a Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+// fakeTerminal records bells and titles.
+type fakeTerminal struct {
+	beeps int
+	title string
+}
+
+func (t *fakeTerminal) Beep() error           { t.beeps++; return nil }
+func (t *fakeTerminal) SetTitle(title string) { t.title = title }
+
+func TestWebhookNotifier(t *testing.T) {
+	var got Alert
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding webhook body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(Config{Webhook: srv.URL}, nil)
+	var errs []error
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	sent := d.Fire(context.Background(), []Alert{{
+		Kind: KindCrash, Entity: "polecat", ID: "gastown/Toast", Rig: "gastown",
+		Severity: "critical", Summary: "crashed (exit 137)",
+	}})
+	if len(sent) != 1 || len(errs) != 0 {
+		t.Fatalf("expected 1 alert delivered without errors, got %d sent, errors %v", len(sent), errs)
+	}
+	if contentType != "application/json" {
+		t.Errorf("expected JSON content type, got %q", contentType)
+	}
+	if got.Kind != KindCrash || got.ID != "gastown/Toast" || got.Rig != "gastown" || got.Time.IsZero() {
+		t.Errorf("unexpected webhook payload: %+v", got)
+	}
+
+	// Non-2xx responses are reported
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	err := (&WebhookNotifier{URL: failing.URL}).Notify(context.Background(), Alert{Kind: KindStuck})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected a 500 error, got %v", err)
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	cmd := `cat > "` + out + `.json"; printf '%s|%s|%s' "$GASTOP_ALERT_KIND" "$GASTOP_ALERT_ID" "$GASTOP_ALERT_SEVERITY" > "` + out + `.env"`
+
+	n := &CommandNotifier{Command: cmd}
+	a := Alert{Kind: KindMergeFailed, Entity: "merge", ID: "polecat/Toast", Severity: "warning", Summary: "merge failed", Time: time.Now()}
+	if err := n.Notify(context.Background(), a); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	data, err := os.ReadFile(out + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Alert
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("stdin was not alert JSON: %v", err)
+	}
+	if got.ID != "polecat/Toast" || got.Summary != "merge failed" {
+		t.Errorf("unexpected stdin alert: %+v", got)
+	}
+
+	env, err := os.ReadFile(out + ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(env) != "merge_failed|polecat/Toast|warning" {
+		t.Errorf("unexpected env: %q", env)
+	}
+
+	// Failures carry stderr
+	err = (&CommandNotifier{Command: "echo boom >&2; exit 3"}).Notify(context.Background(), a)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected failure with stderr, got %v", err)
+	}
+}
+
+func TestTerminalNotifier(t *testing.T) {
+	term := &fakeTerminal{}
+	d := NewDispatcher(Config{Bell: true, Title: true}, term)
+	d.Fire(context.Background(), []Alert{{Kind: KindAgentDown, Entity: "agent", ID: "deacon", Summary: "deacon is not running"}})
+
+	if term.beeps != 1 {
+		t.Errorf("expected 1 beep, got %d", term.beeps)
+	}
+	if !strings.Contains(term.title, "deacon is not running") {
+		t.Errorf("expected the alert in the title, got %q", term.title)
+	}
+
+	var buf strings.Builder
+	w := WriterTerminal{W: &buf}
+	_ = w.Beep()
+	w.SetTitle("hi")
+	if buf.String() != "\a\x1b]0;hi\x07" {
+		t.Errorf("unexpected escape sequences: %q", buf.String())
+	}
+}
+
+func TestDispatcherDedupAndRateLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(Config{Dedup: 10 * time.Minute, RateLimit: 2, Kinds: []string{KindStuck, KindCrash}}, nil)
+	d.now = func() time.Time { return now }
+	rec := &recorder{}
+	d.AddNotifier(rec)
+	var errs []error
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	stuckBead := Alert{Kind: KindStuck, Entity: "bead", ID: "gt-1"}
+	// Filtered by kind
+	d.Fire(context.Background(), []Alert{{Kind: KindAgentDown, Entity: "agent", ID: "mayor"}})
+	if len(rec.alerts) != 0 {
+		t.Fatalf("expected agent_down to be filtered, got %v", rec.alerts)
+	}
+
+	d.Fire(context.Background(), []Alert{stuckBead, stuckBead})
+	if len(rec.alerts) != 1 {
+		t.Fatalf("expected duplicate to be suppressed, got %d", len(rec.alerts))
+	}
+
+	// Rate limit: one slot left this minute
+	d.Fire(context.Background(), []Alert{
+		{Kind: KindStuck, Entity: "bead", ID: "gt-2"},
+		{Kind: KindStuck, Entity: "bead", ID: "gt-3"},
+	})
+	if len(rec.alerts) != 2 || d.Dropped() != 1 || len(errs) != 1 {
+		t.Fatalf("expected 2 sent and 1 dropped with an error, got %d sent, %d dropped, errors %v", len(rec.alerts), d.Dropped(), errs)
+	}
+
+	// The rate window frees up after a minute, the dedup window later
+	now = now.Add(2 * time.Minute)
+	d.Fire(context.Background(), []Alert{stuckBead, {Kind: KindStuck, Entity: "bead", ID: "gt-3"}})
+	if len(rec.alerts) != 3 || rec.alerts[2].ID != "gt-3" {
+		t.Fatalf("expected only gt-3 after the rate window, got %v", rec.alerts)
+	}
+	now = now.Add(10 * time.Minute)
+	d.Fire(context.Background(), []Alert{stuckBead})
+	if len(rec.alerts) != 4 {
+		t.Errorf("expected gt-1 again after the dedup window, got %d", len(rec.alerts))
+	}
+}
+
+func TestDispatcherEscalation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(Config{Dedup: 15 * time.Minute, RateLimit: 10}, nil)
+	d.now = func() time.Time { return now }
+	rec := &recorder{}
+	d.AddNotifier(rec)
+
+	w := NewWatcher()
+	polecats := []model.Polecat{{Name: "Toast", Rig: "gastown"}}
+	d.Fire(context.Background(), w.ObservePolecats(polecats)) // Primes
+
+	polecats[0].Stuck, polecats[0].StuckLevel = true, model.SeverityWarning
+	d.Fire(context.Background(), w.ObservePolecats(polecats))
+	now = now.Add(5 * time.Minute)
+	polecats[0].StuckLevel = model.SeverityCritical
+	d.Fire(context.Background(), w.ObservePolecats(polecats))
+
+	if len(rec.alerts) != 2 || rec.alerts[0].Severity != "warning" || rec.alerts[1].Severity != "critical" {
+		t.Fatalf("expected a warning then a critical alert within the dedup window, got %v", rec.alerts)
+	}
+
+	// A repeat at the same severity is still deduplicated
+	d.Fire(context.Background(), []Alert{rec.alerts[1]})
+	if len(rec.alerts) != 2 {
+		t.Errorf("expected the repeated critical alert to be suppressed, got %d", len(rec.alerts))
+	}
+}
+
+func TestDispatcherErrors(t *testing.T) {
+	d := NewDispatcher(Config{}, nil)
+	d.AddNotifier(notifierFunc(func(ctx context.Context, a Alert) error { return errors.New("down") }))
+	var errs []error
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Fire(context.Background(), []Alert{{Kind: KindStuck, Entity: "bead", ID: "gt-1"}})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "stuck:bead:gt-1") {
+		t.Errorf("expected error naming the alert, got %v", errs)
+	}
+}
+
+type notifierFunc func(ctx context.Context, a Alert) error
+
+func (f notifierFunc) Notify(ctx context.Context, a Alert) error { return f(ctx, a) }
+
+func TestWatcherStuckTransitions(t *testing.T) {
+	w := NewWatcher()
+	beads := []model.Bead{
+		{ID: "gt-1", Stuck: true, StuckLevel: model.SeverityWarning, StuckReason: "No updates for 45m"},
+		{ID: "gt-2"},
+	}
+
+	// The first observation only primes
+	if got := w.ObserveBeads(beads); len(got) != 0 {
+		t.Fatalf("expected no alerts when priming, got %v", got)
+	}
+	if got := w.ObserveBeads(beads); len(got) != 0 {
+		t.Fatalf("expected no alerts without changes, got %v", got)
+	}
+
+	beads[1].Stuck = true
+	beads[1].StuckReason = "No updates for 31m"
+	got := w.ObserveBeads(beads)
+	if len(got) != 1 || got[0].ID != "gt-2" || got[0].Kind != KindStuck || got[0].Severity != "warning" {
+		t.Fatalf("expected gt-2 to alert, got %v", got)
+	}
+
+	// Escalation alerts again; snoozed items do not
+	beads[0].StuckLevel = model.SeverityCritical
+	beads[1].StuckLevel = model.SeverityCritical
+	beads[1].Snoozed = true
+	got = w.ObserveBeads(beads)
+	if len(got) != 1 || got[0].ID != "gt-1" || got[0].Severity != "critical" {
+		t.Fatalf("expected gt-1 to escalate, got %v", got)
+	}
+
+	// Clearing and coming back stuck alerts again
+	beads[0].Stuck = false
+	w.ObserveBeads(beads)
+	beads[0].Stuck = true
+	if got := w.ObserveBeads(beads); len(got) != 1 || got[0].ID != "gt-1" {
+		t.Errorf("expected gt-1 to alert again, got %v", got)
+	}
+
+	polecats := []model.Polecat{{Name: "Toast", Rig: "gastown"}}
+	w.ObservePolecats(polecats)
+	polecats[0].Stuck = true
+	if got := w.ObservePolecats(polecats); len(got) != 1 || got[0].ID != "gastown/Toast" || got[0].Rig != "gastown" {
+		t.Errorf("expected polecat alert, got %v", got)
+	}
+}
+
+func TestWatcherEvents(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(offset time.Duration, typ, actor, payload string) model.Event {
+		e := model.Event{Timestamp: base.Add(offset), Type: typ, Actor: actor, Payload: json.RawMessage(payload)}
+		e.ParsePayload()
+		return e
+	}
+
+	w := NewWatcher()
+	w.Snoozed = func(entity, id string) bool { return id == "gastown/Nux" }
+	events := []model.Event{event(0, "crash", "gastown/polecats/Toast", `{"exit_code":1}`)}
+	if got := w.ObserveEvents(events); len(got) != 0 {
+		t.Fatalf("expected existing events to be skipped, got %v", got)
+	}
+
+	events = append(events,
+		event(time.Minute, "crash", "gastown/polecats/Toast", `{"exit_code":137}`),
+		event(time.Minute, "crash", "gastown/polecats/Nux", `{"exit_code":137}`),
+		event(2*time.Minute, "merge_failed", "gastown/refinery", `{"branch":"polecat/Toast","reason":"conflict","rig":"gastown"}`),
+		event(3*time.Minute, "done", "gastown/polecats/Toast", `{}`),
+	)
+	got := w.ObserveEvents(events)
+	if len(got) != 2 {
+		t.Fatalf("expected crash and merge_failed alerts, got %v", got)
+	}
+	if got[0].Kind != KindCrash || got[0].ID != "gastown/Toast" || !got[0].Time.Equal(base.Add(time.Minute)) {
+		t.Errorf("unexpected crash alert: %+v", got[0])
+	}
+	if got[1].Kind != KindMergeFailed || got[1].ID != "polecat/Toast" || got[1].Rig != "gastown" {
+		t.Errorf("unexpected merge alert: %+v", got[1])
+	}
+
+	if got := w.ObserveEvents(events); len(got) != 0 {
+		t.Errorf("expected seen events to be skipped, got %v", got)
+	}
+}
+
+func TestWatcherAgents(t *testing.T) {
+	w := NewWatcher()
+	agents := []model.Agent{
+		{Name: "deacon", Role: "deacon", Required: true, Running: false},
+		{Name: "mayor", Role: "mayor", Required: true, Running: true},
+	}
+	if got := w.ObserveAgents(agents); len(got) != 0 {
+		t.Fatalf("expected no alerts when priming, got %v", got)
+	}
+
+	agents[1].Running = false
+	got := w.ObserveAgents(agents)
+	if len(got) != 1 || got[0].ID != "mayor" || got[0].Kind != KindAgentDown {
+		t.Fatalf("expected mayor down alert, got %v", got)
+	}
+	if got := w.ObserveAgents(agents); len(got) != 0 {
+		t.Errorf("expected no repeat while still down, got %v", got)
+	}
+}
+
+func TestWatcherUnseenAndScope(t *testing.T) {
+	w := NewWatcher()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	w.Retain = time.Hour
+	stuckBead := model.Bead{ID: "gt-1", Stuck: true}
+
+	w.SetScope(stuck.EntityBead, "")
+	w.ObserveBeads([]model.Bead{stuckBead})
+
+	// Drifting out of the fetched page and back doesn't alert again
+	w.ObserveBeads(nil)
+	if got := w.ObserveBeads([]model.Bead{stuckBead}); len(got) != 0 {
+		t.Errorf("expected no alert on return, got %v", got)
+	}
+
+	// Unless it was gone long enough to be forgotten
+	now = now.Add(2 * time.Hour)
+	w.ObserveBeads(nil)
+	if got := w.ObserveBeads([]model.Bead{stuckBead}); len(got) != 1 {
+		t.Errorf("expected a forgotten item to alert, got %v", got)
+	}
+
+	// Switching scope primes again
+	w.SetScope(stuck.EntityBead, "gastown")
+	focused := []model.Bead{{ID: "gt-7", Stuck: true}, {ID: "gt-8"}}
+	if got := w.ObserveBeads(focused); len(got) != 0 {
+		t.Errorf("expected no alerts after switching rigs, got %v", got)
+	}
+	w.SetScope(stuck.EntityBead, "gastown")
+	focused[1].Stuck = true
+	if got := w.ObserveBeads(focused); len(got) != 1 || got[0].ID != "gt-8" {
+		t.Errorf("expected gt-8 to alert in the same scope, got %v", got)
+	}
+}
@@ -0,0 +1,128 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CommandNotifier runs a shell command for each alert. The alert is
+// written to the command's stdin as JSON and exported in GASTOP_ALERT_*
+// environment variables.
+type CommandNotifier struct {
+	Command string
+}
+
+// Notify implements Notifier.
+func (n *CommandNotifier) Notify(ctx context.Context, a Alert) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Env = append(os.Environ(),
+		"GASTOP_ALERT_KIND="+a.Kind,
+		"GASTOP_ALERT_ENTITY="+a.Entity,
+		"GASTOP_ALERT_ID="+a.ID,
+		"GASTOP_ALERT_RIG="+a.Rig,
+		"GASTOP_ALERT_SEVERITY="+a.Severity,
+		"GASTOP_ALERT_SUMMARY="+a.Summary,
+		"GASTOP_ALERT_TIME="+a.Time.Format(time.RFC3339),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("command failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs each alert as JSON to a URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client // Default: http.DefaultClient
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, a Alert) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Terminal is where the bell rings and the title is shown.
+type Terminal interface {
+	Beep() error
+	SetTitle(title string)
+}
+
+// TerminalNotifier rings the bell and shows the alert in the terminal
+// title.
+type TerminalNotifier struct {
+	Term  Terminal
+	Bell  bool
+	Title bool
+}
+
+// Notify implements Notifier.
+func (n *TerminalNotifier) Notify(ctx context.Context, a Alert) error {
+	if n.Title {
+		n.Term.SetTitle("gastop ⚠ " + a.String())
+	}
+	if n.Bell {
+		return n.Term.Beep()
+	}
+	return nil
+}
+
+// WriterTerminal writes the bell and title escape sequences to a writer,
+// for use outside the TUI.
+type WriterTerminal struct {
+	W io.Writer
+}
+
+// Beep implements Terminal.
+func (t WriterTerminal) Beep() error {
+	_, err := io.WriteString(t.W, "\a")
+	return err
+}
+
+// SetTitle implements Terminal.
+func (t WriterTerminal) SetTitle(title string) {
+	_, _ = io.WriteString(t.W, "\x1b]0;"+title+"\x07")
+}
@@ -0,0 +1,239 @@
+package alert
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/stuck"
+)
+
+// Watcher turns successive observations of town state into alerts on
+// transitions: an item becoming stuck or escalating to critical, a new
+// crash or merge_failed event, or a required agent going down. The first
+// observation of each kind only records state, so starting gastop doesn't
+// replay everything that is already wrong.
+type Watcher struct {
+	// Snoozed reports whether an item is snoozed; crashes of snoozed
+	// polecats are not alerted. Optional.
+	Snoozed func(entity, id string) bool
+	// Retain is how long a stuck item missing from observations keeps its
+	// level, so it doesn't alert again when it comes back (default 24h).
+	Retain time.Duration
+
+	now func() time.Time
+
+	mu     sync.Mutex
+	levels map[string]stuckLevel // entity:id -> stuck level
+	primed map[string]bool       // entity or "events"/"agents" -> observed once
+	scopes map[string]string     // entity or "agents" -> scope last observed
+	down   map[string]bool       // agent full name -> down
+	last   time.Time             // newest event seen
+}
+
+// stuckLevel is the last stuck level of an item and when it was seen.
+type stuckLevel struct {
+	level model.Severity
+	seen  time.Time
+}
+
+// NewWatcher creates a watcher with no observations.
+func NewWatcher() *Watcher {
+	return &Watcher{
+		now:    time.Now,
+		levels: make(map[string]stuckLevel),
+		primed: make(map[string]bool),
+		scopes: make(map[string]string),
+		down:   make(map[string]bool),
+	}
+}
+
+// SetScope records the scope (such as the focused rig) that the next
+// observations of kind cover. When it changes, the next observation only
+// primes, so items already wrong in the new scope don't alert.
+func (w *Watcher) SetScope(kind, scope string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if prev, ok := w.scopes[kind]; ok && prev != scope {
+		w.primed[kind] = false
+	}
+	w.scopes[kind] = scope
+}
+
+// ObserveBeads returns alerts for beads that became stuck or critical.
+// Snoozed beads count as not stuck.
+func (w *Watcher) ObserveBeads(beads []model.Bead) []Alert {
+	items := make([]stuckItem, len(beads))
+	for i := range beads {
+		b := &beads[i]
+		items[i] = stuckItem{b.ID, "", b.Stuck && !b.Snoozed, b.StuckLevel, b.StuckReason}
+	}
+	return w.observeStuck(stuck.EntityBead, items)
+}
+
+// ObservePolecats returns alerts for polecats that became stuck or
+// critical.
+func (w *Watcher) ObservePolecats(polecats []model.Polecat) []Alert {
+	items := make([]stuckItem, len(polecats))
+	for i := range polecats {
+		p := &polecats[i]
+		items[i] = stuckItem{p.FullName(), p.Rig, p.Stuck && !p.Snoozed, p.StuckLevel, p.StuckReason}
+	}
+	return w.observeStuck(stuck.EntityPolecat, items)
+}
+
+// ObserveConvoys returns alerts for convoys that became stuck or critical.
+func (w *Watcher) ObserveConvoys(convoys []model.Convoy) []Alert {
+	items := make([]stuckItem, len(convoys))
+	for i := range convoys {
+		c := &convoys[i]
+		items[i] = stuckItem{c.ID, "", c.Stuck && !c.Snoozed, c.StuckLevel, c.StuckReason}
+	}
+	return w.observeStuck(stuck.EntityConvoy, items)
+}
+
+// stuckItem is the stuck state of one bead, polecat or convoy.
+type stuckItem struct {
+	id     string
+	rig    string
+	stuck  bool
+	level  model.Severity
+	reason string
+}
+
+// observeStuck compares items with their previous level. Items seen not
+// stuck are forgotten, so they alert again if they become stuck. Items
+// missing from the list, such as beads outside the fetched page, keep their
+// level until Retain has passed.
+func (w *Watcher) observeStuck(entity string, items []stuckItem) []Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	primed := w.primed[entity]
+	w.primed[entity] = true
+	now := w.now()
+
+	var alerts []Alert
+	for _, it := range items {
+		key := entity + ":" + it.id
+		if !it.stuck {
+			delete(w.levels, key)
+			continue
+		}
+		level := it.level
+		if level == model.SeverityNone {
+			level = model.SeverityWarning
+		}
+		prev, was := w.levels[key]
+		w.levels[key] = stuckLevel{level, now}
+		if !primed || (was && level.Rank() <= prev.level.Rank()) {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Kind:     KindStuck,
+			Entity:   entity,
+			ID:       it.id,
+			Rig:      it.rig,
+			Severity: string(level),
+			Summary:  it.reason,
+		})
+	}
+
+	retain := w.Retain
+	if retain <= 0 {
+		retain = 24 * time.Hour
+	}
+	prefix := entity + ":"
+	for key, l := range w.levels {
+		if strings.HasPrefix(key, prefix) && now.Sub(l.seen) > retain {
+			delete(w.levels, key)
+		}
+	}
+	return alerts
+}
+
+// ObserveEvents returns alerts for crash and merge_failed events newer
+// than any seen before.
+func (w *Watcher) ObserveEvents(events []model.Event) []Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	primed := w.primed["events"]
+	w.primed["events"] = true
+	last := w.last
+
+	var alerts []Alert
+	for i := range events {
+		e := &events[i]
+		if e.Timestamp.After(w.last) {
+			w.last = e.Timestamp
+		}
+		if !primed || !e.Timestamp.After(last) {
+			continue
+		}
+		switch e.Type {
+		case "crash":
+			agent := stuck.EventAgent(*e)
+			if w.Snoozed != nil && w.Snoozed(stuck.EntityPolecat, agent) {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Kind:     KindCrash,
+				Entity:   stuck.EntityPolecat,
+				ID:       agent,
+				Rig:      e.PayloadData().Common().Rig,
+				Severity: string(model.SeverityCritical),
+				Summary:  e.Summary(),
+				Time:     e.Timestamp,
+			})
+		case "merge_failed":
+			id, rig := e.Actor, ""
+			if p, ok := e.PayloadData().(*model.MergePayload); ok {
+				rig = p.Rig
+				if p.MR != "" {
+					id = p.MR
+				} else if p.Branch != "" {
+					id = p.Branch
+				}
+			}
+			alerts = append(alerts, Alert{
+				Kind:     KindMergeFailed,
+				Entity:   "merge",
+				ID:       id,
+				Rig:      rig,
+				Severity: string(model.SeverityWarning),
+				Summary:  e.Summary(),
+				Time:     e.Timestamp,
+			})
+		}
+	}
+	return alerts
+}
+
+// ObserveAgents returns alerts for required agents that went down.
+func (w *Watcher) ObserveAgents(agents []model.Agent) []Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	primed := w.primed["agents"]
+	w.primed["agents"] = true
+
+	var alerts []Alert
+	for i := range agents {
+		ag := &agents[i]
+		name := ag.FullName()
+		down := ag.IsDown()
+		was := w.down[name]
+		w.down[name] = down
+		if !primed || !down || was {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Kind:     KindAgentDown,
+			Entity:   "agent",
+			ID:       name,
+			Rig:      ag.Rig,
+			Severity: string(model.SeverityCritical),
+			Summary:  ag.Role + " is not running",
+		})
+	}
+	return alerts
+}
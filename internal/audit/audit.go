@@ -0,0 +1,83 @@
+// Package audit records the actions gastop takes on a town, such as
+// nudging or nuking polecats, in an append-only JSON lines file.
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Sources of audited actions.
+const (
+	SourceUser        = "user"
+	SourceRemediation = "remediation"
+)
+
+// Results of an audited action.
+const (
+	ResultOK     = "ok"
+	ResultError  = "error"
+	ResultDryRun = "dry-run" // Would have run; dry-run mode
+)
+
+// Entry is one audited action.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`           // user, remediation
+	Action string    `json:"action"`           // nudge, restart, nuke, sling, ...
+	Target string    `json:"target,omitempty"` // rig/name of the polecat
+	Bead   string    `json:"bead,omitempty"`
+	Policy string    `json:"policy,omitempty"`
+	Step   string    `json:"step,omitempty"` // e.g. "2/3"
+	Reason string    `json:"reason,omitempty"`
+	Result string    `json:"result"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Log appends entries to a file. The zero path disables it.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog returns a log writing to path, created on first use.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file the log writes to.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry, stamping it with the current time if unset.
+func (l *Log) Record(e Entry) error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
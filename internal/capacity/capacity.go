@@ -0,0 +1,123 @@
+// Package capacity matches ready work against idle polecats per rig, to
+// show where the town has work waiting and hands free to take it.
+package capacity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Rig is the capacity of a single rig.
+type Rig struct {
+	Name    string          // "" for beads (and polecats) whose rig is unknown
+	Ready   []model.Bead    // Unclaimed ready beads, highest priority first, then oldest
+	Idle    []model.Polecat // Idle or done polecats with nothing on the hook
+	Working int             // Polecats working or stuck on something
+
+	// TopWait is how long the first ready bead has waited. bd does not
+	// record when a bead's blockers cleared, so this counts from creation.
+	TopWait time.Duration
+}
+
+// Suggestion pairs a ready bead with an idle polecat in the same rig.
+type Suggestion struct {
+	Bead    model.Bead
+	Polecat model.Polecat
+}
+
+// Suggestions pairs the ready beads, in order, with the idle polecats.
+// Beads of unknown rig get none: there's no telling which polecats could
+// work them.
+func (r *Rig) Suggestions() []Suggestion {
+	if r.Name == "" {
+		return nil
+	}
+	n := min(len(r.Ready), len(r.Idle))
+	suggestions := make([]Suggestion, n)
+	for i := range n {
+		suggestions[i] = Suggestion{Bead: r.Ready[i], Polecat: r.Idle[i]}
+	}
+	return suggestions
+}
+
+// Shortfall returns how many ready beads have no idle polecat to take
+// them.
+func (r *Rig) Shortfall() int {
+	return max(len(r.Ready)-len(r.Idle), 0)
+}
+
+// Build groups ready beads and polecats by rig. Beads already hooked to or
+// assigned to a polecat, and epics, which are worked through their
+// children, are left out. rigOf maps a bead ID to its rig; rigs lists rigs
+// to include even when they have neither ready work nor polecats. Rigs are
+// ordered by name, with beads of unknown rig last.
+func Build(ready []model.Bead, polecats []model.Polecat, rigs []string, rigOf func(id string) string, now time.Time) []Rig {
+	byName := make(map[string]*Rig)
+	get := func(name string) *Rig {
+		r := byName[name]
+		if r == nil {
+			r = &Rig{Name: name}
+			byName[name] = r
+		}
+		return r
+	}
+	for _, name := range rigs {
+		get(name)
+	}
+
+	claimed := make(map[string]bool)
+	for i := range polecats {
+		p := polecats[i]
+		if p.HookedBead != "" {
+			claimed[p.HookedBead] = true
+		}
+		if p.AssignedBead != "" {
+			claimed[p.AssignedBead] = true
+		}
+
+		r := get(p.Rig)
+		switch {
+		case (p.State.Is(model.PolecatIdle) || p.State.Is(model.PolecatDone)) && p.HookedBead == "":
+			r.Idle = append(r.Idle, p)
+		case p.State.Is(model.PolecatWorking) || p.State.Is(model.PolecatStuck):
+			r.Working++
+		}
+	}
+
+	for _, b := range ready {
+		if claimed[b.ID] || b.IssueType.Is(model.TypeEpic) {
+			continue
+		}
+		if b.Status != "" && !b.Status.Is(model.StatusOpen) {
+			continue
+		}
+		r := get(rigOf(b.ID))
+		r.Ready = append(r.Ready, b)
+	}
+
+	result := make([]Rig, 0, len(byName))
+	for _, r := range byName {
+		sort.SliceStable(r.Ready, func(i, j int) bool {
+			if r.Ready[i].Priority != r.Ready[j].Priority {
+				return r.Ready[i].Priority < r.Ready[j].Priority
+			}
+			return r.Ready[i].CreatedAt.Before(r.Ready[j].CreatedAt)
+		})
+		sort.SliceStable(r.Idle, func(i, j int) bool {
+			return r.Idle[i].Name < r.Idle[j].Name
+		})
+		if len(r.Ready) > 0 && !r.Ready[0].CreatedAt.IsZero() {
+			r.TopWait = now.Sub(r.Ready[0].CreatedAt)
+		}
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Name == "") != (result[j].Name == "") {
+			return result[j].Name == ""
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,83 @@
+package capacity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ready := []model.Bead{
+		{ID: "gt-1", Status: model.StatusOpen, Priority: 2, CreatedAt: now.Add(-5 * time.Hour)},
+		{ID: "gt-2", Status: model.StatusOpen, Priority: 1, CreatedAt: now.Add(-time.Hour)},
+		{ID: "gt-3", Status: model.StatusOpen, Priority: 1, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "gt-4", Status: model.StatusOpen, Priority: 0},               // Hooked by Nux
+		{ID: "gt-5", Status: model.StatusOpen, IssueType: model.TypeEpic}, // Worked through children
+		{ID: "gt-6", Status: model.StatusInProgress, Priority: 0},         // Already taken
+		{ID: "bd-1", Status: model.StatusOpen, CreatedAt: now.Add(-time.Hour)},
+		{ID: "xx-1", Status: model.StatusOpen},
+	}
+	polecats := []model.Polecat{
+		{Name: "Toast", Rig: "gastown", State: model.PolecatIdle},
+		{Name: "Nux", Rig: "gastown", State: model.PolecatWorking, HookedBead: "gt-4"},
+		{Name: "Ace", Rig: "gastown", State: model.PolecatDone},
+		{Name: "Slit", Rig: "gastown", State: model.PolecatDone, HookedBead: "gt-9"},
+	}
+	rigOf := func(id string) string {
+		switch {
+		case strings.HasPrefix(id, "gt-"):
+			return "gastown"
+		case strings.HasPrefix(id, "bd-"):
+			return "beads"
+		}
+		return ""
+	}
+
+	rigs := Build(ready, polecats, []string{"quiet", "gastown"}, rigOf, now)
+
+	var names []string
+	for _, r := range rigs {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "beads,gastown,quiet," {
+		t.Fatalf("rigs = %q, want beads,gastown,quiet and unknown last", got)
+	}
+
+	gastown := rigs[1]
+	var ids []string
+	for _, b := range gastown.Ready {
+		ids = append(ids, b.ID)
+	}
+	if got := strings.Join(ids, ","); got != "gt-3,gt-2,gt-1" {
+		t.Errorf("ready = %q, want gt-3,gt-2,gt-1 (priority, then oldest)", got)
+	}
+	if len(gastown.Idle) != 2 || gastown.Idle[0].Name != "Ace" || gastown.Idle[1].Name != "Toast" {
+		t.Errorf("idle = %+v, want Ace and Toast", gastown.Idle)
+	}
+	if gastown.Working != 1 || gastown.TopWait != 3*time.Hour || gastown.Shortfall() != 1 {
+		t.Errorf("working %d, top wait %v, shortfall %d", gastown.Working, gastown.TopWait, gastown.Shortfall())
+	}
+
+	suggestions := gastown.Suggestions()
+	if len(suggestions) != 2 || suggestions[0].Bead.ID != "gt-3" || suggestions[0].Polecat.Name != "Ace" ||
+		suggestions[1].Bead.ID != "gt-2" || suggestions[1].Polecat.Name != "Toast" {
+		t.Errorf("unexpected suggestions %+v", suggestions)
+	}
+
+	beads := rigs[0]
+	if len(beads.Ready) != 1 || len(beads.Suggestions()) != 0 || beads.Shortfall() != 1 {
+		t.Errorf("expected beads to have work and no hands, got %+v", beads)
+	}
+	if quiet := rigs[2]; len(quiet.Ready) != 0 || quiet.TopWait != 0 {
+		t.Errorf("expected quiet to be empty, got %+v", quiet)
+	}
+
+	// A polecat without a rig is never paired with work of unknown rig
+	rigs = Build(ready, []model.Polecat{{Name: "Lost", State: model.PolecatIdle}}, nil, rigOf, now)
+	if unknown := rigs[len(rigs)-1]; unknown.Name != "" || len(unknown.Ready) != 1 || len(unknown.Idle) != 1 || unknown.Suggestions() != nil {
+		t.Errorf("expected no suggestions for unknown rig, got %+v", unknown)
+	}
+}
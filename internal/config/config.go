@@ -1,22 +1,34 @@
 package config
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/davidsenack/gastop/internal/alert"
+	"github.com/davidsenack/gastop/internal/remediate"
+	"github.com/davidsenack/gastop/internal/stuck"
 )
 
 // Config holds all gastop configuration.
 type Config struct {
 	RefreshInterval    time.Duration `toml:"refresh_interval"`
+	TownStatusInterval time.Duration `toml:"town_status_interval"` // gt status is slow; polled separately
+	WatchInterval      time.Duration `toml:"watch_interval"`       // gastop watch; unset uses refresh_interval, at least 10s
 	StuckThresholdMins int           `toml:"stuck_threshold_minutes"`
 	LogLines           int           `toml:"log_lines"`
 	ShowLogs           bool          `toml:"show_logs"`
+	Rig                string        `toml:"rig"` // Focus on a single rig ("" = all rigs)
 
 	Paths   PathsConfig   `toml:"paths"`
 	Filters FiltersConfig `toml:"filters"`
+	Stuck   stuck.Config  `toml:"stuck"`
+	Alerts  alert.Config  `toml:"alerts"`
+
+	Remediation remediate.Config `toml:"remediation"`
 }
 
 // PathsConfig holds path settings.
@@ -24,6 +36,7 @@ type PathsConfig struct {
 	GTBinary string `toml:"gt_binary"`
 	BDBinary string `toml:"bd_binary"`
 	TownRoot string `toml:"town_root"`
+	StateDir string `toml:"state_dir"` // Default: $XDG_STATE_HOME/gastop or ~/.local/state/gastop
 }
 
 // FiltersConfig holds default filter settings.
@@ -36,6 +49,7 @@ type FiltersConfig struct {
 func DefaultConfig() *Config {
 	return &Config{
 		RefreshInterval:    1 * time.Second,
+		TownStatusInterval: 30 * time.Second,
 		StuckThresholdMins: 30,
 		LogLines:           10,
 		ShowLogs:           true,
@@ -73,6 +87,13 @@ func Load() (*Config, error) {
 		}
 	}
 
+	if err := cfg.Stuck.Validate(); err != nil {
+		return nil, fmt.Errorf("stuck rules: %w", err)
+	}
+	if err := cfg.Remediation.Validate(); err != nil {
+		return nil, fmt.Errorf("remediation: %w", err)
+	}
+
 	// Auto-detect town root if not set
 	if cfg.Paths.TownRoot == "" {
 		cfg.Paths.TownRoot = detectTownRoot()
@@ -81,6 +102,40 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// StuckConfig returns the stuck detection settings, with the base threshold
+// taken from stuck_threshold_minutes unless [stuck] sets one.
+func (c *Config) StuckConfig() stuck.Config {
+	sc := c.Stuck
+	if sc.Threshold <= 0 {
+		sc.Threshold = time.Duration(c.StuckThresholdMins) * time.Minute
+	}
+	return sc
+}
+
+// TownStateDir returns the directory for gastop's state about the current
+// town, such as snoozed items. Each town gets its own subdirectory, named
+// after the town root and a short hash of its path.
+func (c *Config) TownStateDir() string {
+	base := c.Paths.StateDir
+	if base == "" {
+		if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+			base = filepath.Join(xdg, "gastop")
+		} else {
+			base = filepath.Join(os.Getenv("HOME"), ".local", "state", "gastop")
+		}
+	}
+
+	town := "default"
+	if root := c.Paths.TownRoot; root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+		sum := sha256.Sum256([]byte(root))
+		town = fmt.Sprintf("%s-%x", filepath.Base(root), sum[:4])
+	}
+	return filepath.Join(base, "towns", town)
+}
+
 // detectTownRoot tries to find a Gas Town workspace by looking for markers.
 func detectTownRoot() string {
 	// Check GT_TOWN_ROOT environment variable first
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/alert"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/stuck"
+)
+
+// observe raises alerts for what changed in a checked snapshot.
+func (e *Engine) observe(ctx context.Context, s *Snapshot) {
+	if e.watcher == nil {
+		return
+	}
+	// Switching rigs re-primes, so the new rig's stuck items don't alert
+	scope := ""
+	if s.Rig != nil {
+		scope = s.Rig.Name
+	}
+
+	var alerts []alert.Alert
+	if s.HasEvents {
+		alerts = append(alerts, e.watcher.ObserveEvents(s.Events)...)
+	}
+	if s.HasPolecats {
+		e.watcher.SetScope(stuck.EntityPolecat, scope)
+		alerts = append(alerts, e.watcher.ObservePolecats(s.Polecats)...)
+	}
+	if s.HasBeads {
+		// Tracked beads are only current when convoys were fetched
+		beads := s.Beads
+		if s.HasConvoys {
+			beads = append(append([]model.Bead(nil), s.Beads...), s.Tracked...)
+		}
+		e.watcher.SetScope(stuck.EntityBead, scope)
+		alerts = append(alerts, e.watcher.ObserveBeads(beads)...)
+	}
+	if s.HasConvoys {
+		e.watcher.SetScope(stuck.EntityConvoy, scope)
+		alerts = append(alerts, e.watcher.ObserveConvoys(s.Convoys)...)
+	}
+	e.fire(ctx, alerts)
+}
+
+// fire delivers alerts in the background so slow hooks don't hold up
+// refreshes. Deliveries outlive ctx's cancellation, bounded by the alert
+// timeout, so alerts raised just before shutdown still go out.
+func (e *Engine) fire(ctx context.Context, alerts []alert.Alert) {
+	if e.alerts == nil || len(alerts) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	e.pending.Add(1)
+	go func() {
+		defer e.pending.Done()
+		for _, a := range e.alerts.Fire(ctx, alerts) {
+			if e.OnAlert != nil {
+				e.OnAlert(a)
+			}
+		}
+	}()
+}
+
+// snoozed reports whether an item has a snooze in effect.
+func (e *Engine) snoozed(entity, id string) bool {
+	sn, ok := e.snoozes.Get(entity, id)
+	return ok && !sn.Expired(time.Now())
+}
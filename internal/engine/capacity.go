@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/capacity"
+)
+
+// Capacity fetches ready work and matches it against the polecats of the
+// last refresh, for the rigs in focus.
+func (e *Engine) Capacity(ctx context.Context) ([]capacity.Rig, error) {
+	ready, err := e.ReadyBeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	focused := e.FocusedRig()
+	e.mu.RLock()
+	polecats := e.polecats
+	var rigs []string
+	switch {
+	case focused != nil:
+		rigs = []string{focused.Name}
+	case e.townStatus != nil:
+		for _, rig := range e.townStatus.Rigs {
+			rigs = append(rigs, rig.Name)
+		}
+	}
+	e.mu.RUnlock()
+
+	return capacity.Build(ready, polecats, rigs, e.RigOf, time.Now()), nil
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// ConvoyBeads fetches a convoy's full status and the tracked beads outside
+// the last bead list, for a drill-down. The extra beads are checked like
+// those of a refresh, and the convoy's progress is recomputed against
+// them. c carries the stuck state of the last refresh, which gt convoy
+// status doesn't report.
+func (e *Engine) ConvoyBeads(ctx context.Context, c model.Convoy) (model.Convoy, []model.Bead) {
+	full := c
+	if status, err := e.adapter.GetConvoyStatus(ctx, c.ID); err == nil {
+		full = *status
+		full.Merge(c)
+		full.Stuck, full.StuckReason = c.Stuck, c.StuckReason
+		full.StuckLevel, full.StuckSince = c.StuckLevel, c.StuckSince
+		full.Snoozed = c.Snoozed
+	}
+
+	e.mu.RLock()
+	known := e.beads
+	e.mu.RUnlock()
+	inList := make(map[string]bool, len(known))
+	for _, b := range known {
+		inList[b.ID] = true
+	}
+
+	var extra []model.Bead
+	for _, id := range full.TrackedIDs {
+		if inList[id] {
+			continue
+		}
+		if b, err := e.adapter.GetBead(ctx, id); err == nil {
+			extra = append(extra, *b)
+		}
+	}
+	e.checkMu.Lock()
+	e.checkBeads(extra)
+	e.checkMu.Unlock()
+
+	convoys := []model.Convoy{full}
+	e.adapter.EnrichConvoyProgress(ctx, convoys, append(append([]model.Bead(nil), known...), extra...))
+	return convoys[0], extra
+}
@@ -0,0 +1,256 @@
+// Package engine runs gastop's refresh cycle independent of any UI: it
+// fetches town state, runs stuck detection over it and raises alerts. The
+// TUI and the headless watch mode both drive an Engine.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/davidsenack/gastop/internal/adapter"
+	"github.com/davidsenack/gastop/internal/alert"
+	"github.com/davidsenack/gastop/internal/audit"
+	"github.com/davidsenack/gastop/internal/config"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/remediate"
+	"github.com/davidsenack/gastop/internal/stuck"
+)
+
+// eventHistorySize is how many events are read for derived views such as
+// the merge queue; the events panel shows only the most recent LogLines.
+const eventHistorySize = 500
+
+// Engine fetches and checks town state. Refreshes should not overlap; the
+// accessors are safe to call from any goroutine.
+type Engine struct {
+	adapter    *adapter.Adapter
+	config     *config.Config
+	stuck      *stuck.Detector
+	history    *stuck.History // Stuck state across refreshes
+	snoozes    *stuck.Snoozes // Acknowledged and snoozed items, per town
+	alerts     *alert.Dispatcher
+	watcher    *alert.Watcher        // Turns refreshes into alerts; nil when alerts are off
+	audit      *audit.Log            // Actions taken on the town
+	remediator *remediate.Remediator // nil unless EnableRemediation was called
+
+	// OnError is called with failures that don't stop a refresh, such as a
+	// source failing or an alert not being delivered. Optional; set before
+	// the first refresh.
+	OnError func(msg string)
+	// OnAlert is called with each alert after delivery. Optional.
+	OnAlert func(a alert.Alert)
+	// OnRemediation is called with each remediation step after it runs
+	// (or would have, in dry-run mode). Optional.
+	OnRemediation func(entry audit.Entry)
+
+	mu         sync.RWMutex
+	rig        string // Focused rig ("" = all rigs)
+	townStatus *adapter.TownStatus
+	polecats   []model.Polecat // Last good data, for sources that fail
+	beads      []model.Bead
+	events     []model.Event
+
+	pending sync.WaitGroup // Alert deliveries in flight
+
+	// checkMu serializes the stuck checks and the history and snooze
+	// updates that follow them, between a refresh and a convoy drill-down.
+	checkMu sync.Mutex
+}
+
+// New creates an engine for the configured town. term receives the bell
+// and title alerts, if any. The engine is usable even when an error is
+// returned: a snooze file that can't be read starts out empty.
+func New(cfg *config.Config, adp *adapter.Adapter, term alert.Terminal) (*Engine, error) {
+	e := &Engine{
+		adapter: adp,
+		config:  cfg,
+		stuck:   newDetector(cfg),
+		history: stuck.NewHistory(cfg.StuckConfig().History),
+		rig:     cfg.Rig,
+	}
+	e.stuck.RigOf = e.RigOf
+
+	if cfg.Alerts.Enabled() {
+		e.alerts = alert.NewDispatcher(cfg.Alerts, term)
+		e.alerts.OnError = func(err error) {
+			e.report("alerts: " + err.Error())
+		}
+		e.watcher = alert.NewWatcher()
+		e.watcher.Snoozed = e.snoozed
+		e.watcher.Retain = cfg.StuckConfig().History.Retain
+	}
+
+	e.audit = audit.NewLog(filepath.Join(cfg.TownStateDir(), "audit.jsonl"))
+
+	var err error
+	e.snoozes, err = stuck.LoadSnoozes(filepath.Join(cfg.TownStateDir(), "snoozes.json"))
+	if err != nil {
+		err = fmt.Errorf("snoozes: %w", err)
+	}
+	return e, err
+}
+
+// EnableRemediation runs the configured remediation policies on each
+// refresh. Only gastop watch calls it, so a TUI open next to the daemon
+// doesn't act on the same polecats. It returns false when remediation is
+// disabled in the config.
+func (e *Engine) EnableRemediation() bool {
+	e.remediator = remediate.New(e.config.Remediation, e.adapter, e.audit)
+	if e.remediator == nil {
+		return false
+	}
+	e.remediator.OnStep = func(entry audit.Entry) {
+		if e.OnRemediation != nil {
+			e.OnRemediation(entry)
+		}
+	}
+	e.remediator.OnError = func(err error) {
+		e.report("remediation: " + err.Error())
+	}
+	return true
+}
+
+// newDetector builds the stuck detector from the config. Load validates
+// the rules, so the fallback only guards configs built in code.
+func newDetector(cfg *config.Config) *stuck.Detector {
+	d, err := stuck.NewDetectorFromConfig(cfg.StuckConfig())
+	if err != nil {
+		return stuck.NewDetector(cfg.StuckThresholdMins)
+	}
+	return d
+}
+
+// Detector returns the stuck detector.
+func (e *Engine) Detector() *stuck.Detector {
+	return e.stuck
+}
+
+// History returns the stuck history.
+func (e *Engine) History() *stuck.History {
+	return e.history
+}
+
+// Snoozes returns the town's snoozed items.
+func (e *Engine) Snoozes() *stuck.Snoozes {
+	return e.snoozes
+}
+
+// Audit returns the audit log of actions taken on the town.
+func (e *Engine) Audit() *audit.Log {
+	return e.audit
+}
+
+// Rig returns the focused rig name ("" = all rigs).
+func (e *Engine) Rig() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rig
+}
+
+// SetRig focuses later refreshes on a rig ("" = all rigs).
+func (e *Engine) SetRig(name string) {
+	e.mu.Lock()
+	e.rig = name
+	e.mu.Unlock()
+}
+
+// TownStatus returns the last gt status, or nil before the first answer.
+func (e *Engine) TownStatus() *adapter.TownStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.townStatus
+}
+
+// FocusedRig returns the focused rig, or nil when showing all rigs.
+func (e *Engine) FocusedRig() *adapter.RigStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.adapter.ResolveRig(e.townStatus, e.rig)
+}
+
+// RigOf returns the rig owning a bead ID, once gt status has answered.
+func (e *Engine) RigOf(id string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.townStatus == nil {
+		return ""
+	}
+	return e.townStatus.RigOf(id)
+}
+
+// Refresh fetches a snapshot, checks it and raises alerts for what
+// changed.
+func (e *Engine) Refresh(ctx context.Context) Snapshot {
+	s := e.fetch(ctx)
+	e.check(ctx, &s)
+
+	e.mu.Lock()
+	if s.HasPolecats {
+		e.polecats = s.Polecats
+	}
+	if s.HasBeads {
+		e.beads = s.Beads
+	}
+	if s.HasEvents {
+		e.events = s.Events
+	}
+	e.mu.Unlock()
+
+	e.observe(ctx, &s)
+	if e.remediator != nil && s.HasPolecats {
+		e.remediator.Observe(ctx, s.Polecats)
+	}
+	return s
+}
+
+// RefreshTownStatus fetches gt status and returns the agents in focus:
+// town-level agents always, rig agents only for the focused rig.
+func (e *Engine) RefreshTownStatus(ctx context.Context) ([]model.Agent, error) {
+	status, err := e.adapter.GetTownStatus(ctx)
+	if err != nil {
+		return nil, err // Keep the last known status
+	}
+
+	e.mu.Lock()
+	e.townStatus = status
+	scope := e.rig
+	var agents []model.Agent
+	for _, ag := range status.Agents() {
+		if e.rig == "" || ag.Rig == "" || ag.Rig == e.rig {
+			agents = append(agents, ag)
+		}
+	}
+	model.AttachSessionStarts(agents, e.events)
+	e.mu.Unlock()
+
+	if e.watcher != nil {
+		e.watcher.SetScope("agents", scope)
+		e.fire(ctx, e.watcher.ObserveAgents(agents))
+	}
+	return agents, nil
+}
+
+// Wait waits for alert deliveries and remediation steps in flight, for a
+// clean shutdown.
+func (e *Engine) Wait() {
+	e.pending.Wait()
+	if e.remediator != nil {
+		e.remediator.Wait()
+	}
+}
+
+// report passes a non-fatal error to OnError.
+func (e *Engine) report(msg string) {
+	if e.OnError != nil {
+		e.OnError(msg)
+	}
+}
+
+// snoozeError reports a failure to save snoozes.
+func (e *Engine) snoozeError(err error) {
+	if err != nil {
+		e.report("snoozes: " + err.Error())
+	}
+}
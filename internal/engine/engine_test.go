@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/adapter"
+	"github.com/davidsenack/gastop/internal/alert"
+	"github.com/davidsenack/gastop/internal/config"
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// fakeTown is a town whose gt and bd are shell scripts answering from
+// JSON files the test can rewrite between refreshes.
+type fakeTown struct {
+	t   *testing.T
+	dir string
+}
+
+func newFakeTown(t *testing.T) *fakeTown {
+	dir := t.TempDir()
+	f := &fakeTown{t: t, dir: dir}
+
+	f.script("gt", `case "$1 $2" in
+"polecat list") cat "`+dir+`/polecats.json" ;;
+"convoy list") echo '[]' ;;
+"status --json") cat "`+dir+`/status.json" ;;
+*) exit 1 ;;
+esac`)
+	f.script("bd", `case "$*" in
+*"-t convoy"*) echo '[]' ;;
+"list"*) cat "`+dir+`/beads.json" ;;
+"ready"*) cat "`+dir+`/ready.json" ;;
+"blocked"*) cat "`+dir+`/blocked.json" ;;
+"show "*) cat "`+dir+`/show-$2.json" ;;
+*) exit 1 ;;
+esac`)
+
+	f.write("polecats.json", `[{"name":"Toast","rig":"gastown","state":"idle"}]`)
+	f.write("status.json", `{"name":"town","mayor":{"running":true},"deacon":{"running":true}}`)
+	f.write(".events.jsonl", "")
+	f.write("ready.json", "[]")
+	f.write("blocked.json", "[]")
+	f.beads()
+	return f
+}
+
+// script writes an executable shell script.
+func (f *fakeTown) script(name, body string) {
+	path := filepath.Join(f.dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// write writes a file in the town.
+func (f *fakeTown) write(name, content string) {
+	if err := os.WriteFile(filepath.Join(f.dir, name), []byte(content), 0644); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// beads writes the bead list: in_progress beads, last updated two hours
+// ago.
+func (f *fakeTown) beads(ids ...string) {
+	var list []map[string]any
+	for _, id := range ids {
+		list = append(list, map[string]any{
+			"id": id, "title": id, "status": "in_progress", "priority": 2,
+			"created_at": time.Now().Add(-3 * time.Hour), "updated_at": time.Now().Add(-2 * time.Hour),
+		})
+	}
+	data, _ := json.Marshal(list)
+	if list == nil {
+		data = []byte("[]")
+	}
+	f.write("beads.json", string(data))
+}
+
+// config returns a config for the town with alerts sent to a recorder
+// command writing into the town.
+func (f *fakeTown) config() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Paths.TownRoot = f.dir
+	cfg.Paths.StateDir = filepath.Join(f.dir, "state")
+	cfg.Alerts.Command = `cat >> "` + f.dir + `/alerts.jsonl"; echo >> "` + f.dir + `/alerts.jsonl"`
+	return cfg
+}
+
+func (f *fakeTown) adapter() *adapter.Adapter {
+	return adapter.New(filepath.Join(f.dir, "gt"), filepath.Join(f.dir, "bd"), f.dir)
+}
+
+func TestEngineRefresh(t *testing.T) {
+	town := newFakeTown(t)
+	town.beads("gt-1")
+
+	e, err := New(town.config(), town.adapter(), nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var mu sync.Mutex
+	var errs []string
+	var sent []alert.Alert
+	e.OnError = func(msg string) {
+		mu.Lock()
+		errs = append(errs, msg)
+		mu.Unlock()
+	}
+	e.OnAlert = func(a alert.Alert) {
+		mu.Lock()
+		sent = append(sent, a)
+		mu.Unlock()
+	}
+	ctx := context.Background()
+
+	if _, err := e.RefreshTownStatus(ctx); err != nil {
+		t.Fatalf("RefreshTownStatus: %v", err)
+	}
+	if e.TownStatus() == nil || e.TownStatus().Name != "town" {
+		t.Errorf("expected town status to be kept, got %+v", e.TownStatus())
+	}
+
+	// The first refresh finds gt-1 stuck but only primes alerts
+	s := e.Refresh(ctx)
+	if !s.HasPolecats || !s.HasBeads || !s.HasConvoys {
+		t.Fatalf("expected all sources, got %+v (errors %v)", s, errs)
+	}
+	if len(s.Beads) != 1 || !s.Beads[0].Stuck || s.Beads[0].StuckSince.IsZero() {
+		t.Fatalf("expected gt-1 stuck with history, got %+v", s.Beads)
+	}
+	e.Wait()
+	if len(sent) != 0 {
+		t.Fatalf("expected no alerts on the first refresh, got %v", sent)
+	}
+
+	// A newly stuck bead alerts through the command
+	town.beads("gt-1", "gt-2")
+	e.Refresh(ctx)
+	e.Wait()
+	if len(sent) != 1 || sent[0].ID != "gt-2" || sent[0].Kind != alert.KindStuck {
+		t.Fatalf("expected gt-2 alert, got %v (errors %v)", sent, errs)
+	}
+	data, err := os.ReadFile(filepath.Join(town.dir, "alerts.jsonl"))
+	if err != nil || !strings.Contains(string(data), `"id":"gt-2"`) {
+		t.Errorf("expected the command to get the alert, got %q (%v)", data, err)
+	}
+
+	// A failing source with nothing cached is reported and left out
+	town.script("gt", "exit 1")
+	e, _ = New(town.config(), town.adapter(), nil)
+	errs = nil
+	e.OnError = func(msg string) { errs = append(errs, msg) }
+	s = e.Refresh(ctx)
+	if s.HasPolecats || !s.HasBeads {
+		t.Errorf("expected polecats to fail and beads to succeed, got %+v", s)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], "polecats: ") {
+		t.Errorf("expected the polecats error to be reported, got %v", errs)
+	}
+}
+
+func TestEngineRigFocus(t *testing.T) {
+	town := newFakeTown(t)
+	town.write("status.json", `{"name":"town","rigs":[{"name":"gastown","prefix":"gt","state":"active"}]}`)
+
+	e, _ := New(town.config(), town.adapter(), nil)
+	if _, err := e.RefreshTownStatus(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if e.FocusedRig() != nil {
+		t.Errorf("expected no focus by default")
+	}
+
+	e.SetRig("gastown")
+	if e.Rig() != "gastown" || e.FocusedRig() == nil || e.FocusedRig().Name != "gastown" {
+		t.Errorf("expected gastown focus, got %q", e.Rig())
+	}
+	if rig := e.RigOf("gt-123"); rig != "gastown" {
+		t.Errorf("RigOf(gt-123) = %q, want gastown", rig)
+	}
+}
+
+func TestEngineCapacity(t *testing.T) {
+	town := newFakeTown(t)
+	town.write("status.json", `{"name":"town","rigs":[{"name":"gastown","prefix":"gt"},{"name":"beads","prefix":"bd"}]}`)
+	town.write("ready.json", `[{"id":"gt-1","title":"fix","status":"open","priority":1},
+		{"id":"bd-2","title":"docs","status":"open","priority":2}]`)
+
+	e, _ := New(town.config(), town.adapter(), nil)
+	ctx := context.Background()
+	if _, err := e.RefreshTownStatus(ctx); err != nil {
+		t.Fatal(err)
+	}
+	e.Refresh(ctx)
+
+	rigs, err := e.Capacity(ctx)
+	if err != nil {
+		t.Fatalf("Capacity: %v", err)
+	}
+	if len(rigs) != 2 || rigs[0].Name != "beads" || rigs[1].Name != "gastown" {
+		t.Fatalf("expected both rigs, got %+v", rigs)
+	}
+	suggestions := rigs[1].Suggestions()
+	if len(suggestions) != 1 || suggestions[0].Bead.ID != "gt-1" || suggestions[0].Polecat.Name != "Toast" {
+		t.Errorf("expected gt-1 for idle Toast, got %+v", suggestions)
+	}
+
+	// Focusing a rig scopes the ready work to it
+	e.SetRig("gastown")
+	rigs, err = e.Capacity(ctx)
+	if err != nil || len(rigs) != 1 || rigs[0].Name != "gastown" || len(rigs[0].Ready) != 1 {
+		t.Errorf("expected only gastown, got %+v (%v)", rigs, err)
+	}
+}
+
+func TestEngineBlockedBeads(t *testing.T) {
+	town := newFakeTown(t)
+	town.beads("gt-1")
+	town.write("blocked.json", `[{"id":"gt-5","title":"ship","status":"blocked","blocked_by":["gt-1","gt-2"]},
+		{"id":"gt-6","title":"docs","status":"open","dependency_count":1}]`)
+	town.write("show-gt-2.json", `{"id":"gt-2","title":"review","status":"closed"}`)
+	town.write("show-gt-6.json", `{"id":"gt-6","title":"docs","status":"open","blocked_by":["gt-5"]}`)
+	town.write("show-gt-5.json", `{"id":"gt-5","title":"ship","status":"blocked"}`)
+
+	e, _ := New(town.config(), town.adapter(), nil)
+	ctx := context.Background()
+	e.Refresh(ctx)
+
+	beads, err := e.BlockedBeads(ctx)
+	if err != nil {
+		t.Fatalf("BlockedBeads: %v", err)
+	}
+	if len(beads) != 2 {
+		t.Fatalf("expected 2 blocked beads, got %+v", beads)
+	}
+
+	// gt-1 comes from the bead list, gt-2 from bd show
+	blockers := beads[0].Blockers
+	if len(blockers) != 2 || blockers[0].Status != model.StatusInProgress || blockers[1].Title != "review" ||
+		!blockers[1].Resolved() {
+		t.Errorf("unexpected blockers of gt-5: %+v", blockers)
+	}
+	// bd blocked gave only a count; the IDs come from bd show
+	if b := beads[1].Blockers; len(b) != 1 || b[0].ID != "gt-5" || b[0].Status != model.StatusBlocked {
+		t.Errorf("unexpected blockers of gt-6: %+v", b)
+	}
+}
+
+func TestEngineConvoyBeads(t *testing.T) {
+	town := newFakeTown(t)
+	town.beads("gt-1")
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	town.write("show-gt-9.json", `{"id":"gt-9","title":"elsewhere","status":"in_progress","updated_at":"`+old+`"}`)
+
+	e, _ := New(town.config(), town.adapter(), nil)
+	ctx := context.Background()
+	e.Refresh(ctx)
+
+	// gt convoy status fails in the fake town; the listed convoy is used
+	convoy, extra := e.ConvoyBeads(ctx, model.Convoy{ID: "hq-cv-1", TrackedIDs: []string{"gt-1", "gt-9"}})
+	if len(extra) != 1 || extra[0].ID != "gt-9" {
+		t.Fatalf("expected only gt-9 to be fetched, got %+v", extra)
+	}
+	if !extra[0].Stuck {
+		t.Error("expected the fetched bead to be checked like a refreshed one")
+	}
+	if _, ok := e.History().Get("bead", "gt-9"); !ok {
+		t.Error("expected the fetched bead in the stuck history")
+	}
+	if convoy.TotalCount != 2 {
+		t.Errorf("expected progress over both tracked beads, got %d", convoy.TotalCount)
+	}
+}
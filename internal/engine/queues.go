@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/davidsenack/gastop/internal/adapter"
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// queueLimit bounds the beads fetched for the ready and blocked queues.
+const queueLimit = 100
+
+// ReadyBeads returns the ready work (bd ready) of the rig in focus, or of
+// the whole town.
+func (e *Engine) ReadyBeads(ctx context.Context) ([]model.Bead, error) {
+	if rig := e.FocusedRig(); rig != nil {
+		return e.adapter.ListBeads(ctx, rig.ScopeBeads(adapter.BeadListOpts{Ready: true, Limit: queueLimit}))
+	}
+	return e.adapter.ListReadyBeads(ctx, queueLimit)
+}
+
+// BlockedBeads returns the blocked work (bd blocked) of the rig in focus,
+// or of the whole town, with each bead's blockers and their statuses.
+func (e *Engine) BlockedBeads(ctx context.Context) ([]model.Bead, error) {
+	var beads []model.Bead
+	var err error
+	if rig := e.FocusedRig(); rig != nil {
+		beads, err = e.adapter.ListBeads(ctx, rig.ScopeBeads(adapter.BeadListOpts{Blocked: true, Limit: queueLimit}))
+	} else {
+		beads, err = e.adapter.ListBlockedBeads(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	known := e.beads
+	e.mu.RUnlock()
+	e.adapter.EnrichBlockers(ctx, beads, known)
+	return beads, nil
+}
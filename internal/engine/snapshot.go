@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/davidsenack/gastop/internal/adapter"
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Snapshot is one refresh of town state. Sources are fetched in parallel
+// and joined before stuck detection, so checks that span sources (a
+// convoy's tracked beads and swarm) see state from the same refresh.
+type Snapshot struct {
+	Rig      *adapter.RigStatus // Focused rig, nil for all rigs
+	Polecats []model.Polecat
+	Beads    []model.Bead
+	Tracked  []model.Bead // Tracked convoy beads outside the bead list
+	Convoys  []model.Convoy
+	Events   []model.Event
+
+	// Which sources were fetched; failed ones keep their previous data
+	HasPolecats bool
+	HasBeads    bool
+	HasConvoys  bool
+	HasEvents   bool
+}
+
+// fetch fetches all sources in parallel and waits for them.
+func (e *Engine) fetch(ctx context.Context) Snapshot {
+	s := Snapshot{Rig: e.FocusedRig()}
+	rigName := ""
+	if s.Rig != nil {
+		rigName = s.Rig.Name
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(4)
+
+	// Each goroutine writes only its own snapshot fields
+	go func() {
+		defer wg.Done()
+		polecats, err := e.adapter.ListPolecats(ctx, rigName)
+		if err != nil {
+			e.report("polecats: " + err.Error())
+			return // Use cached data
+		}
+
+		// Enrich working polecats with hooked bead info and details
+		for i := range polecats {
+			if polecats[i].State.Is(model.PolecatWorking) {
+				// Fetch detailed status for working polecats
+				_ = e.adapter.EnrichPolecatWithDetails(ctx, &polecats[i])
+			}
+		}
+		// Fetch hooked bead info (only for working/done polecats)
+		e.adapter.EnrichPolecatsWithHooks(ctx, polecats)
+		s.Polecats, s.HasPolecats = polecats, true
+	}()
+
+	go func() {
+		defer wg.Done()
+		opts := adapter.BeadListOpts{Limit: 100}
+		if s.Rig != nil {
+			opts = s.Rig.ScopeBeads(opts)
+		}
+		beads, err := e.adapter.ListBeads(ctx, opts)
+		if err != nil {
+			return // Use cached data
+		}
+		s.Beads, s.HasBeads = beads, true
+	}()
+
+	go func() {
+		defer wg.Done()
+		convoys, err := e.adapter.ListConvoys(ctx, adapter.ConvoyListOpts{})
+		if err != nil {
+			e.report("convoys: " + err.Error())
+			return // Use cached data
+		}
+		s.Convoys, s.HasConvoys = convoys, true
+	}()
+
+	// Direct file read, very fast
+	go func() {
+		defer wg.Done()
+		events, err := e.adapter.TailEvents(ctx, eventHistorySize)
+		if err != nil {
+			return // Events are optional
+		}
+		s.Events, s.HasEvents = events, true
+	}()
+
+	wg.Wait()
+	return s
+}
+
+// check runs the enrichment that spans sources and the stuck checks, in
+// dependency order: events feed polecat and bead checks, and convoys are
+// checked last against the checked (and snooze-marked) beads and polecats.
+func (e *Engine) check(ctx context.Context, s *Snapshot) {
+	e.checkMu.Lock()
+	defer e.checkMu.Unlock()
+
+	// Sources that failed fall back to the last good data for lookups
+	e.mu.RLock()
+	polecats, beads := s.Polecats, s.Beads
+	if !s.HasPolecats {
+		polecats = e.polecats
+	}
+	if !s.HasBeads {
+		beads = e.beads
+	}
+	e.mu.RUnlock()
+
+	if s.HasEvents {
+		e.stuck.ObserveEvents(s.Events)
+	}
+	if s.HasPolecats {
+		e.stuck.CheckPolecats(s.Polecats)
+		e.history.ApplyPolecats(s.Polecats)
+		e.snoozeError(e.snoozes.ApplyPolecats(s.Polecats))
+	}
+	if s.HasBeads {
+		e.adapter.EnrichMolecules(ctx, s.Beads, polecats)
+		e.checkBeads(s.Beads)
+	}
+	if !s.HasConvoys {
+		return
+	}
+
+	s.Tracked = e.adapter.EnrichConvoyProgress(ctx, s.Convoys, beads)
+	e.checkBeads(s.Tracked)
+	if s.Rig != nil {
+		s.Convoys = s.Rig.FilterConvoys(s.Convoys)
+	}
+	all := append(append([]model.Bead(nil), beads...), s.Tracked...)
+	e.stuck.CheckConvoys(s.Convoys, all, polecats)
+	e.history.ApplyConvoys(s.Convoys)
+	e.snoozeError(e.snoozes.ApplyConvoys(s.Convoys))
+}
+
+// checkBeads runs the stuck checks on beads and applies their history and
+// snoozes. The caller holds e.checkMu.
+func (e *Engine) checkBeads(beads []model.Bead) {
+	e.stuck.CheckBeads(beads)
+	e.history.ApplyBeads(beads)
+	e.snoozeError(e.snoozes.ApplyBeads(beads))
+}
@@ -0,0 +1,402 @@
+// Package graph builds the bead dependency DAG and answers questions about
+// it: cycles, critical paths and the root blockers holding up the most work.
+package graph
+
+import (
+	"context"
+	"sort"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Node is a bead in the dependency graph.
+type Node struct {
+	Bead       model.Bead
+	Upstream   []string // Beads this one waits on
+	Downstream []string // Beads waiting on this one
+	Parent     string   // Epic or molecule this bead belongs to
+	Children   []string // Beads belonging to this epic or molecule
+	Missing    bool     // Referenced by another bead but not in the data set
+}
+
+// waitsOn returns what must finish before the bead can: its blockers and
+// its children.
+func (n *Node) waitsOn() []string {
+	return append(append([]string(nil), n.Upstream...), n.Children...)
+}
+
+// feeds returns what finishing the bead moves forward: its dependents and
+// its parent.
+func (n *Node) feeds() []string {
+	if n.Parent == "" {
+		return n.Downstream
+	}
+	return append(append([]string(nil), n.Downstream...), n.Parent)
+}
+
+// Graph is a dependency graph over beads. Edges point from a blocker to the
+// work it blocks, and from tracked beads to their convoy. Parent/child links
+// are kept apart from edges: an epic waits on its children for critical
+// paths and rollups, but they aren't its blockers.
+type Graph struct {
+	nodes map[string]*Node
+	order []string // Insertion order, for deterministic output
+}
+
+// RootBlocker is an unblocked, unfinished bead with work waiting on it.
+type RootBlocker struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Unblocks int    `json:"unblocks"` // Transitive count of unfinished dependents
+}
+
+// Analysis summarizes the graph for display and JSON output.
+type Analysis struct {
+	Cycles        [][]string          `json:"cycles,omitempty"`
+	CriticalPaths map[string][]string `json:"critical_paths,omitempty"`
+	RootBlockers  []RootBlocker       `json:"root_blockers,omitempty"`
+}
+
+// Build creates a graph from bead data.
+func Build(beads []model.Bead) *Graph {
+	g := &Graph{nodes: make(map[string]*Node)}
+	for _, b := range beads {
+		n := g.node(b.ID)
+		n.Bead = b
+		n.Missing = false
+	}
+	for _, b := range beads {
+		for _, blocker := range b.BlockedBy {
+			g.addEdge(blocker, b.ID)
+		}
+		for _, blocked := range b.Blocks {
+			g.addEdge(b.ID, blocked)
+		}
+		g.addChild(b.Parent, b.ID)
+		for _, child := range b.Children {
+			g.addChild(b.ID, child)
+		}
+	}
+	return g
+}
+
+// AddConvoys adds convoys as nodes that depend on their tracked beads.
+func (g *Graph) AddConvoys(convoys []model.Convoy) {
+	for _, c := range convoys {
+		n := g.node(c.ID)
+		n.Missing = false
+		n.Bead = model.Bead{ID: c.ID, Title: c.Title, Status: c.Status, IssueType: model.TypeConvoy}
+		for _, id := range c.TrackedIDs {
+			g.addEdge(id, c.ID)
+		}
+	}
+}
+
+// node returns the node for id, creating a placeholder if needed.
+func (g *Graph) node(id string) *Node {
+	n, ok := g.nodes[id]
+	if !ok {
+		n = &Node{Bead: model.Bead{ID: id}, Missing: true}
+		g.nodes[id] = n
+		g.order = append(g.order, id)
+	}
+	return n
+}
+
+// addEdge records that from must finish before to, ignoring duplicates.
+func (g *Graph) addEdge(from, to string) {
+	if from == "" || to == "" {
+		return
+	}
+	src, dst := g.node(from), g.node(to)
+	for _, id := range src.Downstream {
+		if id == to {
+			return
+		}
+	}
+	src.Downstream = append(src.Downstream, to)
+	dst.Upstream = append(dst.Upstream, from)
+}
+
+// addChild records that child belongs to parent, ignoring duplicates.
+func (g *Graph) addChild(parent, child string) {
+	if parent == "" || child == "" || parent == child {
+		return
+	}
+	p, c := g.node(parent), g.node(child)
+	c.Parent = parent
+	for _, id := range p.Children {
+		if id == child {
+			return
+		}
+	}
+	p.Children = append(p.Children, child)
+}
+
+// Node returns the node for a bead ID, or nil if unknown.
+func (g *Graph) Node(id string) *Node {
+	return g.nodes[id]
+}
+
+// Len returns the number of nodes.
+func (g *Graph) Len() int {
+	return len(g.nodes)
+}
+
+// isDone returns true if the bead is known to be closed. Missing beads
+// count as unfinished since we can't tell.
+func (g *Graph) isDone(id string) bool {
+	n := g.nodes[id]
+	return n != nil && !n.Missing && n.Bead.Status.IsDone()
+}
+
+// Cycles returns every dependency cycle (strongly connected component with
+// more than one bead, or a bead that blocks itself).
+func (g *Graph) Cycles() [][]string {
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var connect func(id string)
+	connect = func(id string) {
+		indices[id] = index
+		lowlink[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		selfLoop := false
+		for _, next := range g.nodes[id].feeds() {
+			if next == id {
+				selfLoop = true
+			}
+			if _, seen := indices[next]; !seen {
+				connect(next)
+				lowlink[id] = min(lowlink[id], lowlink[next])
+			} else if onStack[next] {
+				lowlink[id] = min(lowlink[id], indices[next])
+			}
+		}
+
+		if lowlink[id] == indices[id] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == id {
+					break
+				}
+			}
+			if len(component) > 1 || selfLoop {
+				sort.Strings(component)
+				cycles = append(cycles, component)
+			}
+		}
+	}
+
+	for _, id := range g.order {
+		if _, seen := indices[id]; !seen {
+			connect(id)
+		}
+	}
+	return cycles
+}
+
+// CriticalPath returns the longest chain of unfinished beads that must
+// complete before target, ordered from the first bead to work on through
+// target itself. Returns nil if target is unknown or already closed.
+func (g *Graph) CriticalPath(target string) []string {
+	if g.nodes[target] == nil || g.isDone(target) {
+		return nil
+	}
+
+	memo := make(map[string][]string)
+	visiting := make(map[string]bool)
+
+	var longest func(id string) []string
+	longest = func(id string) []string {
+		if path, ok := memo[id]; ok {
+			return path
+		}
+		if visiting[id] {
+			return nil // Cycle; stop here rather than loop forever
+		}
+		visiting[id] = true
+
+		var best []string
+		for _, up := range g.nodes[id].waitsOn() {
+			if g.isDone(up) {
+				continue
+			}
+			if path := longest(up); len(path) > len(best) {
+				best = path
+			}
+		}
+		visiting[id] = false
+
+		path := append(append([]string{}, best...), id)
+		memo[id] = path
+		return path
+	}
+
+	return longest(target)
+}
+
+// CriticalPaths returns the critical path to every unfinished epic,
+// molecule and convoy that has outstanding work.
+func (g *Graph) CriticalPaths() map[string][]string {
+	paths := make(map[string][]string)
+	for _, id := range g.order {
+		n := g.nodes[id]
+		switch n.Bead.IssueType.Canonical() {
+		case model.TypeEpic, model.TypeMolecule, model.TypeConvoy:
+		default:
+			continue
+		}
+		if path := g.CriticalPath(id); len(path) > 1 {
+			paths[id] = path
+		}
+	}
+	return paths
+}
+
+// Downstream returns the transitive set of unfinished beads waiting on id,
+// including the epics it rolls up into.
+func (g *Graph) Downstream(id string) []string {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	var result []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		n := g.nodes[cur]
+		if n == nil {
+			continue
+		}
+		for _, next := range n.feeds() {
+			if seen[next] || g.isDone(next) {
+				continue
+			}
+			seen[next] = true
+			result = append(result, next)
+			queue = append(queue, next)
+		}
+	}
+	return result
+}
+
+// Neighborhood returns the beads around id, level by level: upstream[0]
+// holds its direct blockers, upstream[1] their blockers, and so on up to
+// depth levels; downstream likewise. Each bead appears once, at the level
+// nearest to id.
+func (g *Graph) Neighborhood(id string, depth int) (upstream, downstream [][]string) {
+	if g.nodes[id] == nil {
+		return nil, nil
+	}
+	walk := func(next func(n *Node) []string) [][]string {
+		seen := map[string]bool{id: true}
+		frontier := []string{id}
+		var levels [][]string
+		for i := 0; i < depth && len(frontier) > 0; i++ {
+			var level []string
+			for _, cur := range frontier {
+				for _, nb := range next(g.nodes[cur]) {
+					if !seen[nb] {
+						seen[nb] = true
+						level = append(level, nb)
+					}
+				}
+			}
+			if len(level) == 0 {
+				break
+			}
+			levels = append(levels, level)
+			frontier = level
+		}
+		return levels
+	}
+	upstream = walk(func(n *Node) []string { return n.Upstream })
+	downstream = walk(func(n *Node) []string { return n.Downstream })
+	return upstream, downstream
+}
+
+// IsDone returns true if the bead is known to be closed.
+func (g *Graph) IsDone(id string) bool {
+	return g.isDone(id)
+}
+
+// RootBlockers returns unfinished beads with no unfinished blockers of
+// their own, ranked by how much downstream work finishing them unblocks.
+// limit <= 0 returns all of them.
+func (g *Graph) RootBlockers(limit int) []RootBlocker {
+	var roots []RootBlocker
+	for _, id := range g.order {
+		n := g.nodes[id]
+		if g.isDone(id) || n.Bead.IssueType.Is(model.TypeConvoy) {
+			continue
+		}
+		blocked := false
+		for _, up := range n.waitsOn() {
+			if !g.isDone(up) {
+				blocked = true
+				break
+			}
+		}
+		if blocked {
+			continue
+		}
+		if unblocks := len(g.Downstream(id)); unblocks > 0 {
+			roots = append(roots, RootBlocker{ID: id, Title: n.Bead.Title, Unblocks: unblocks})
+		}
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		if roots[i].Unblocks != roots[j].Unblocks {
+			return roots[i].Unblocks > roots[j].Unblocks
+		}
+		return roots[i].ID < roots[j].ID
+	})
+	if limit > 0 && len(roots) > limit {
+		roots = roots[:limit]
+	}
+	return roots
+}
+
+// Analyze computes cycles, critical paths and the top root blockers.
+func (g *Graph) Analyze() Analysis {
+	return Analysis{
+		Cycles:        g.Cycles(),
+		CriticalPaths: g.CriticalPaths(),
+		RootBlockers:  g.RootBlockers(10),
+	}
+}
+
+// Enrich fills in dependency IDs for beads whose list output only carried
+// counts, fetching at most limit beads with fetch (typically bd show).
+func Enrich(ctx context.Context, beads []model.Bead, fetch func(ctx context.Context, id string) (*model.Bead, error), limit int) {
+	fetched := 0
+	for i := range beads {
+		if beads[i].HasDependencyIDs() || beads[i].Status.IsDone() {
+			continue
+		}
+		if fetched >= limit {
+			return
+		}
+		fetched++
+
+		detail, err := fetch(ctx, beads[i].ID)
+		if err != nil {
+			continue
+		}
+		beads[i].BlockedBy = detail.BlockedBy
+		beads[i].Blocks = detail.Blocks
+		if beads[i].Parent == "" {
+			beads[i].Parent = detail.Parent
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// testBeads is a small epic with a chain, a fan-out and a closed blocker:
+//
+//	gt-1 (closed) → gt-2 → gt-3 → gt-5
+//	                gt-2 → gt-4
+//	gt-3, gt-4, gt-5 are children of epic gt-e
+func testBeads() []model.Bead {
+	return []model.Bead{
+		{ID: "gt-e", Title: "Epic", Status: "open", IssueType: "epic"},
+		{ID: "gt-1", Status: "closed", Blocks: []string{"gt-2"}},
+		{ID: "gt-2", Title: "Foundation", Status: "in_progress"},
+		{ID: "gt-3", Status: "open", BlockedBy: []string{"gt-2"}, Parent: "gt-e"},
+		{ID: "gt-4", Status: "open", BlockedBy: []string{"gt-2"}, Parent: "gt-e"},
+		{ID: "gt-5", Status: "open", BlockedBy: []string{"gt-3"}, Parent: "gt-e"},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testBeads())
+
+	if g.Len() != 6 {
+		t.Errorf("Len = %d, want 6", g.Len())
+	}
+	n := g.Node("gt-2")
+	if !reflect.DeepEqual(n.Upstream, []string{"gt-1"}) {
+		t.Errorf("gt-2 upstream = %v, want [gt-1]", n.Upstream)
+	}
+	if !reflect.DeepEqual(n.Downstream, []string{"gt-3", "gt-4"}) {
+		t.Errorf("gt-2 downstream = %v, want [gt-3 gt-4]", n.Downstream)
+	}
+	if g.Node("nope") != nil {
+		t.Error("expected nil for unknown node")
+	}
+
+	// Parent links are kept apart from blocking edges
+	epic, child := g.Node("gt-e"), g.Node("gt-3")
+	if len(epic.Upstream) != 0 || !reflect.DeepEqual(epic.Children, []string{"gt-3", "gt-4", "gt-5"}) {
+		t.Errorf("epic upstream = %v, children = %v", epic.Upstream, epic.Children)
+	}
+	if child.Parent != "gt-e" || !reflect.DeepEqual(child.Downstream, []string{"gt-5"}) {
+		t.Errorf("gt-3 parent = %q, downstream = %v", child.Parent, child.Downstream)
+	}
+
+	// Blocks and BlockedBy describing the same edge are deduplicated
+	g = Build([]model.Bead{{ID: "a", Blocks: []string{"b"}}, {ID: "b", BlockedBy: []string{"a"}}})
+	if len(g.Node("a").Downstream) != 1 {
+		t.Errorf("expected deduplicated edge, got %v", g.Node("a").Downstream)
+	}
+
+	// References to unknown beads create placeholders
+	g = Build([]model.Bead{{ID: "a", BlockedBy: []string{"other-1"}}})
+	if n := g.Node("other-1"); n == nil || !n.Missing {
+		t.Error("expected missing placeholder for other-1")
+	}
+}
+
+func TestCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		beads []model.Bead
+		want  [][]string
+	}{
+		{"acyclic", testBeads(), nil},
+		{
+			name: "two-bead cycle",
+			beads: []model.Bead{
+				{ID: "a", BlockedBy: []string{"b"}},
+				{ID: "b", BlockedBy: []string{"a"}},
+				{ID: "c", BlockedBy: []string{"a"}},
+			},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name:  "self loop",
+			beads: []model.Bead{{ID: "a", BlockedBy: []string{"a"}}},
+			want:  [][]string{{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Build(tt.beads).Cycles()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	g := Build(testBeads())
+
+	want := []string{"gt-2", "gt-3", "gt-5", "gt-e"}
+	if got := g.CriticalPath("gt-e"); !reflect.DeepEqual(got, want) {
+		t.Errorf("CriticalPath(gt-e) = %v, want %v", got, want)
+	}
+	if got := g.CriticalPath("gt-1"); got != nil {
+		t.Errorf("CriticalPath of closed bead = %v, want nil", got)
+	}
+
+	paths := g.CriticalPaths()
+	if len(paths) != 1 || !reflect.DeepEqual(paths["gt-e"], want) {
+		t.Errorf("CriticalPaths = %v", paths)
+	}
+
+	// Convoys depend on their tracked beads
+	g.AddConvoys([]model.Convoy{{ID: "hq-1", Status: "open", TrackedIDs: []string{"gt-4"}}})
+	if got := g.CriticalPath("hq-1"); !reflect.DeepEqual(got, []string{"gt-2", "gt-4", "hq-1"}) {
+		t.Errorf("CriticalPath(hq-1) = %v", got)
+	}
+
+	// Cycles terminate
+	g = Build([]model.Bead{
+		{ID: "a", BlockedBy: []string{"b"}},
+		{ID: "b", BlockedBy: []string{"a"}},
+	})
+	if got := g.CriticalPath("a"); len(got) != 2 {
+		t.Errorf("CriticalPath in cycle = %v, want 2 beads", got)
+	}
+}
+
+func TestRootBlockers(t *testing.T) {
+	beads := append(testBeads(),
+		model.Bead{ID: "gt-6", Status: "open", Blocks: []string{"gt-7"}},
+		model.Bead{ID: "gt-7", Status: "open"},
+	)
+	got := Build(beads).RootBlockers(0)
+
+	want := []RootBlocker{
+		{ID: "gt-2", Title: "Foundation", Unblocks: 4}, // gt-3, gt-4, gt-5, gt-e
+		{ID: "gt-6", Unblocks: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RootBlockers = %+v, want %+v", got, want)
+	}
+
+	if got := Build(beads).RootBlockers(1); len(got) != 1 {
+		t.Errorf("RootBlockers(1) returned %d", len(got))
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	beads := []model.Bead{
+		{ID: "a", Status: "open", BlockerCount: 1},
+		{ID: "b", Status: "open"},
+		{ID: "c", Status: "closed", BlockerCount: 1},
+		{ID: "d", Status: "open", DependentCount: 1},
+	}
+	var fetched []string
+	fetch := func(ctx context.Context, id string) (*model.Bead, error) {
+		fetched = append(fetched, id)
+		if id == "d" {
+			return nil, errors.New("bd show failed")
+		}
+		return &model.Bead{ID: id, BlockedBy: []string{"b"}}, nil
+	}
+
+	Enrich(context.Background(), beads, fetch, 10)
+	if !reflect.DeepEqual(fetched, []string{"a", "d"}) {
+		t.Errorf("fetched = %v, want [a d]", fetched)
+	}
+	if !reflect.DeepEqual(beads[0].BlockedBy, []string{"b"}) {
+		t.Errorf("a.BlockedBy = %v, want [b]", beads[0].BlockedBy)
+	}
+
+	fetched = nil
+	beads[0].BlockedBy = nil
+	Enrich(context.Background(), beads, fetch, 1)
+	if len(fetched) != 1 {
+		t.Errorf("expected fetch limit of 1, fetched %v", fetched)
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	g := Build(testBeads())
+
+	tests := []struct {
+		id             string
+		depth          int
+		wantUpstream   [][]string
+		wantDownstream [][]string
+	}{
+		{"gt-3", 1, [][]string{{"gt-2"}}, [][]string{{"gt-5"}}},
+		{"gt-3", 2, [][]string{{"gt-2"}, {"gt-1"}}, [][]string{{"gt-5"}}},
+		{"gt-1", 3, nil, [][]string{{"gt-2"}, {"gt-3", "gt-4"}, {"gt-5"}}},
+		{"gt-e", 2, nil, nil}, // Children aren't blockers
+		{"missing", 2, nil, nil},
+	}
+
+	for _, tt := range tests {
+		up, down := g.Neighborhood(tt.id, tt.depth)
+		if !reflect.DeepEqual(up, tt.wantUpstream) {
+			t.Errorf("Neighborhood(%s, %d) upstream = %v, want %v", tt.id, tt.depth, up, tt.wantUpstream)
+		}
+		if !reflect.DeepEqual(down, tt.wantDownstream) {
+			t.Errorf("Neighborhood(%s, %d) downstream = %v, want %v", tt.id, tt.depth, down, tt.wantDownstream)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -10,9 +11,9 @@ type Bead struct {
 	ID          string     `json:"id"`
 	Title       string     `json:"title"`
 	Description string     `json:"description,omitempty"`
-	Status      string     `json:"status"` // open, in_progress, blocked, deferred, closed
-	Priority    int        `json:"priority"` // 0-4, 0=highest
-	IssueType   string     `json:"issue_type"` // bug, feature, task, epic, molecule, agent
+	Status      BeadStatus `json:"status"`     // open, in_progress, blocked, deferred, closed
+	Priority    int        `json:"priority"`   // 0-4, 0=highest
+	IssueType   IssueType  `json:"issue_type"` // bug, feature, task, epic, molecule, agent
 	Owner       string     `json:"owner,omitempty"`
 	Assignee    string     `json:"assignee,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
@@ -21,16 +22,55 @@ type Bead struct {
 	CloseReason string     `json:"close_reason,omitempty"`
 	Labels      []string   `json:"labels,omitempty"`
 	Parent      string     `json:"parent,omitempty"`
+	Children    []string   `json:"children,omitempty"` // From bd show
 	Ephemeral   bool       `json:"ephemeral,omitempty"`
 
 	// Dependency info (from bd show)
 	Blocks    []string `json:"blocks,omitempty"`
 	BlockedBy []string `json:"blocked_by,omitempty"`
 
+	// Dependency counts (from bd list, which omits the IDs themselves)
+	BlockerCount   int `json:"dependency_count,omitempty"`
+	DependentCount int `json:"dependent_count,omitempty"`
+
+	// Workflow steps for molecule beads (populated separately)
+	Molecule *Molecule `json:"-"`
+
+	// BlockedBy resolved to the blockers' current state (populated
+	// separately, for the blocked queue)
+	Blockers []Blocker `json:"-"`
+
 	// Computed fields
-	Stuck       bool   `json:"-"`
-	StuckReason string `json:"-"`
-	Age         string `json:"-"` // Human-readable age
+	Stuck       bool      `json:"-"`
+	StuckReason string    `json:"-"`
+	StuckLevel  Severity  `json:"-"`
+	StuckSince  time.Time `json:"-"` // Start of the current stuck episode
+	Snoozed     bool      `json:"-"` // Acknowledged or snoozed by the user
+	Age         string    `json:"-"` // Human-readable age
+
+	// Fields from newer bd versions, kept for JSON output
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a bead, keeping unknown fields in Extra.
+func (b *Bead) UnmarshalJSON(data []byte) error {
+	type plain Bead
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, plain{})
+	b.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a bead, including any unknown fields.
+func (b Bead) MarshalJSON() ([]byte, error) {
+	type plain Bead
+	data, err := json.Marshal(plain(b))
+	if err != nil {
+		return nil, err
+	}
+	return withExtra(data, b.Extra, plain{})
 }
 
 // StatusIcon returns a status indicator character.
@@ -38,16 +78,16 @@ func (b *Bead) StatusIcon() string {
 	if b.Stuck {
 		return "⚠"
 	}
-	switch b.Status {
-	case "closed":
+	switch b.Status.Canonical() {
+	case StatusClosed, StatusTombstone:
 		return "✓"
-	case "in_progress":
+	case StatusInProgress, StatusHooked:
 		return "●"
-	case "blocked":
+	case StatusBlocked:
 		return "✗"
-	case "deferred":
+	case StatusDeferred:
 		return "⏸"
-	case "open":
+	case StatusOpen:
 		return "○"
 	default:
 		return "?"
@@ -61,7 +101,24 @@ func (b *Bead) PriorityString() string {
 
 // ComputeAge sets the human-readable age field.
 func (b *Bead) ComputeAge() {
-	b.Age = humanizeDuration(time.Since(b.CreatedAt))
+	b.Age = HumanizeDuration(time.Since(b.CreatedAt))
+}
+
+// ClosedAgo returns how long ago the bead was closed, or "" if open.
+func (b *Bead) ClosedAgo() string {
+	if b.ClosedAt == nil {
+		return ""
+	}
+	return HumanizeDuration(time.Since(*b.ClosedAt))
+}
+
+// UpdatedAgo returns how long ago the bead was last updated, or "" if
+// unknown.
+func (b *Bead) UpdatedAgo() string {
+	if b.UpdatedAt.IsZero() {
+		return ""
+	}
+	return HumanizeDuration(time.Since(b.UpdatedAt))
 }
 
 // TimeSinceUpdate returns duration since last update.
@@ -71,16 +128,45 @@ func (b *Bead) TimeSinceUpdate() time.Duration {
 
 // IsBlocked returns true if the bead has blockers.
 func (b *Bead) IsBlocked() bool {
-	return len(b.BlockedBy) > 0 || b.Status == "blocked"
+	return len(b.BlockedBy) > 0 || b.Status.Is(StatusBlocked)
+}
+
+// IsMolecule returns true for multi-step workflow beads.
+func (b *Bead) IsMolecule() bool {
+	return b.IssueType.Is(TypeMolecule)
 }
 
 // DependencyCount returns the number of dependencies.
 func (b *Bead) DependencyCount() int {
-	return len(b.BlockedBy)
+	if len(b.BlockedBy) > b.BlockerCount {
+		return len(b.BlockedBy)
+	}
+	return b.BlockerCount
+}
+
+// HasDependencyIDs returns false if bd reported dependencies that the
+// bead's Blocks/BlockedBy lists don't include (list output omits them).
+func (b *Bead) HasDependencyIDs() bool {
+	return len(b.BlockedBy) >= b.BlockerCount && len(b.Blocks) >= b.DependentCount
+}
+
+// Blocker is a bead blocking another, as it stands now. Status is empty
+// when the blocker couldn't be looked up.
+type Blocker struct {
+	ID       string
+	Title    string
+	Status   BeadStatus
+	Assignee string
+}
+
+// Resolved returns true if the blocker is closed and no longer blocks.
+func (b *Blocker) Resolved() bool {
+	return b.Status.IsDone()
 }
 
-// humanizeDuration converts a duration to a short human-readable string.
-func humanizeDuration(d time.Duration) string {
+// HumanizeDuration converts a duration to a short human-readable string,
+// such as "45s", "12m" or "3d".
+func HumanizeDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%ds", int(d.Seconds()))
 	}
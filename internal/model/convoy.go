@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -8,27 +9,58 @@ import (
 // Convoy represents a batch of tracked work across rigs.
 // Supports both gt convoy list format and bd list -t convoy format.
 type Convoy struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Status      string    `json:"status"` // open, closed
-	TrackedIDs  []string  `json:"tracked_ids,omitempty"`
-	TotalCount  int       `json:"total_count"`
-	ClosedCount int       `json:"closed_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Status      BeadStatus `json:"status"` // open, closed
+	TrackedIDs  []string   `json:"tracked_ids,omitempty"`
+	TotalCount  int        `json:"total_count"`
+	ClosedCount int        `json:"closed_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 	ClosedAt    *time.Time `json:"closed_at,omitempty"`
-	Owner       string    `json:"owner,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
 
 	// Fields from bd list -t convoy format
-	Description     string `json:"description,omitempty"`
-	Priority        int    `json:"priority,omitempty"`
-	IssueType       string `json:"issue_type,omitempty"`
-	DependencyCount int    `json:"dependency_count,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	Priority        int       `json:"priority,omitempty"`
+	IssueType       IssueType `json:"issue_type,omitempty"`
+	DependencyCount int       `json:"dependency_count,omitempty"`
+
+	// Per-status breakdown, set when derived from tracked beads
+	Counts *ConvoyCounts `json:"status_counts,omitempty"`
 
 	// Computed fields
-	Progress    float64 `json:"-"` // 0.0 - 1.0
-	Stuck       bool    `json:"-"`
-	StuckReason string  `json:"-"`
+	Progress    float64   `json:"-"` // 0.0 - 1.0
+	Stuck       bool      `json:"-"`
+	StuckReason string    `json:"-"`
+	StuckLevel  Severity  `json:"-"`
+	StuckSince  time.Time `json:"-"` // Start of the current stuck episode
+	Snoozed     bool      `json:"-"` // Acknowledged or snoozed by the user
+	StuckBeads  []string  `json:"-"` // Tracked beads that are stuck, worst first
+
+	// Fields from newer gt/bd versions, kept for JSON output
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a convoy, keeping unknown fields in Extra.
+func (c *Convoy) UnmarshalJSON(data []byte) error {
+	type plain Convoy
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, plain{})
+	c.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a convoy, including any unknown fields.
+func (c Convoy) MarshalJSON() ([]byte, error) {
+	type plain Convoy
+	data, err := json.Marshal(plain(c))
+	if err != nil {
+		return nil, err
+	}
+	return withExtra(data, c.Extra, plain{})
 }
 
 // ComputeProgress calculates the completion percentage.
@@ -42,6 +74,126 @@ func (c *Convoy) ComputeProgress() {
 	}
 }
 
+// ConvoyCounts breaks a convoy's tracked beads down by status.
+type ConvoyCounts struct {
+	Open       int `json:"open"`
+	InProgress int `json:"in_progress"`
+	Blocked    int `json:"blocked"`
+	Closed     int `json:"closed"`
+	Unknown    int `json:"unknown,omitempty"` // Tracked beads that couldn't be resolved
+}
+
+// Total returns the number of tracked beads counted.
+func (c ConvoyCounts) Total() int {
+	return c.Open + c.InProgress + c.Blocked + c.Closed + c.Unknown
+}
+
+// ComputeFromTracked derives progress from the live state of the tracked
+// beads, overriding the reported counts. Deferred beads count as open.
+// Returns false (leaving the convoy unchanged) if no tracked bead resolves.
+func (c *Convoy) ComputeFromTracked(lookup func(id string) (Bead, bool)) bool {
+	if len(c.TrackedIDs) == 0 {
+		return false
+	}
+
+	var counts ConvoyCounts
+	for _, id := range c.TrackedIDs {
+		b, ok := lookup(id)
+		if !ok {
+			counts.Unknown++
+			continue
+		}
+		switch {
+		case b.Status.IsDone():
+			counts.Closed++
+		case b.Status.IsActive():
+			counts.InProgress++
+		case b.Status.Is(StatusBlocked):
+			counts.Blocked++
+		default:
+			counts.Open++
+		}
+	}
+	if counts.Unknown == counts.Total() {
+		return false
+	}
+
+	c.Counts = &counts
+	c.TotalCount = counts.Total()
+	c.ClosedCount = counts.Closed
+	c.Progress = float64(c.ClosedCount) / float64(c.TotalCount)
+	return true
+}
+
+// Merge fills fields missing from c with those from another listing of the
+// same convoy.
+func (c *Convoy) Merge(other Convoy) {
+	if len(c.TrackedIDs) == 0 {
+		c.TrackedIDs = other.TrackedIDs
+	}
+	if c.TotalCount == 0 {
+		c.TotalCount, c.ClosedCount = other.TotalCount, other.ClosedCount
+	}
+	if c.DependencyCount == 0 {
+		c.DependencyCount = other.DependencyCount
+	}
+	if c.Title == "" {
+		c.Title = other.Title
+	}
+	if c.Description == "" {
+		c.Description = other.Description
+	}
+	if other.UpdatedAt.After(c.UpdatedAt) {
+		c.UpdatedAt = other.UpdatedAt
+	}
+}
+
+// DedupeConvoys merges convoys listed more than once (e.g. by both gt and
+// bd), keeping the first listing's order.
+func DedupeConvoys(convoys []Convoy) []Convoy {
+	seen := make(map[string]int, len(convoys))
+	deduped := convoys[:0]
+	for _, c := range convoys {
+		if i, ok := seen[c.ID]; ok {
+			deduped[i].Merge(c)
+			continue
+		}
+		seen[c.ID] = len(deduped)
+		deduped = append(deduped, c)
+	}
+	return deduped
+}
+
+// ClosedAgo returns how long ago the convoy landed, or "" if still open.
+func (c *Convoy) ClosedAgo() string {
+	if c.ClosedAt == nil {
+		return ""
+	}
+	return HumanizeDuration(time.Since(*c.ClosedAt))
+}
+
+// Tracks returns true if the convoy tracks the bead.
+func (c *Convoy) Tracks(id string) bool {
+	for _, t := range c.TrackedIDs {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Swarm returns the polecats working on the convoy's tracked beads.
+func (c *Convoy) Swarm(polecats []Polecat) []Polecat {
+	var swarm []Polecat
+	for _, pc := range polecats {
+		if (pc.HookedBead != "" && c.Tracks(pc.HookedBead)) ||
+			(pc.AssignedBead != "" && c.Tracks(pc.AssignedBead)) {
+			swarm = append(swarm, pc)
+		}
+	}
+	return swarm
+}
+
 // ProgressString returns a human-readable progress string.
 func (c *Convoy) ProgressString() string {
 	return fmt.Sprintf("%d/%d", c.ClosedCount, c.TotalCount)
@@ -49,10 +201,10 @@ func (c *Convoy) ProgressString() string {
 
 // StatusIcon returns a status indicator character.
 func (c *Convoy) StatusIcon() string {
-	switch c.Status {
-	case "closed":
+	switch c.Status.Canonical() {
+	case StatusClosed:
 		return "✓"
-	case "open":
+	case StatusOpen:
 		if c.Stuck {
 			return "⚠"
 		}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -48,37 +50,35 @@ type Event struct {
 	Visibility string          `json:"visibility,omitempty"`
 
 	// Parsed payload fields (type-dependent)
-	TargetRig     string `json:"-"`
-	TargetPolecat string `json:"-"`
-	TargetBead    string `json:"-"`
-	Message       string `json:"-"`
+	Data          Payload `json:"-"`
+	TargetRig     string  `json:"-"`
+	TargetPolecat string  `json:"-"`
+	TargetBead    string  `json:"-"`
+	SessionID     string  `json:"-"`
+	Message       string  `json:"-"`
 }
 
-// ParsePayload extracts common fields from the payload based on event type.
+// ParsePayload decodes the payload into its typed form and extracts the
+// common fields.
 func (e *Event) ParsePayload() {
-	if len(e.Payload) == 0 {
-		return
-	}
+	e.Data = DecodePayload(e.Type, e.Payload)
 
-	var p map[string]interface{}
-	if err := json.Unmarshal(e.Payload, &p); err != nil {
-		return
-	}
+	c := e.Data.Common()
+	e.TargetRig = c.Rig
+	e.TargetPolecat = c.Polecat
+	e.TargetBead = c.Bead
+	e.SessionID = c.SessionID
+	// target can contain rig/polecat path
+	e.Message = c.Target
+}
 
-	// Extract common fields
-	if v, ok := p["rig"].(string); ok {
-		e.TargetRig = v
-	}
-	if v, ok := p["polecat"].(string); ok {
-		e.TargetPolecat = v
-	}
-	if v, ok := p["bead"].(string); ok {
-		e.TargetBead = v
-	}
-	if v, ok := p["target"].(string); ok {
-		// target can contain rig/polecat path
-		e.Message = v
+// PayloadData returns the typed payload, decoding it if ParsePayload
+// hasn't been called.
+func (e *Event) PayloadData() Payload {
+	if e.Data != nil {
+		return e.Data
 	}
+	return DecodePayload(e.Type, e.Payload)
 }
 
 // Icon returns an icon for the event type.
@@ -92,13 +92,82 @@ func (e *Event) Icon() string {
 // Summary returns a short description of the event.
 func (e *Event) Summary() string {
 	// Handle special cases with dynamic content
-	switch e.Type {
-	case "spawn":
-		if e.TargetPolecat != "" {
-			return "spawned " + e.TargetPolecat
+	switch p := e.PayloadData().(type) {
+	case *SessionStartPayload:
+		s := "session started"
+		if p.Role != "" {
+			s += " as " + p.Role
+		}
+		if p.CWD != "" {
+			s += " in " + p.CWD
+		}
+		return s
+	case *SpawnPayload:
+		if p.Polecat == "" {
+			return "spawned"
+		}
+		s := "spawned " + p.Polecat
+		if p.Branch != "" {
+			s += " on " + p.Branch
 		}
-		return "spawned"
-	case "sling":
+		return s
+	case *HandoffPayload:
+		s := "handed off"
+		if p.To != "" {
+			s += " to " + p.To
+		}
+		return withDetail(s, p.Subject, p.Reason)
+	case *DonePayload:
+		s := "completed"
+		if p.Bead != "" {
+			s += " " + p.Bead
+		}
+		if p.Exit != "" && !strings.EqualFold(p.Exit, "completed") {
+			s += " (" + strings.ToLower(p.Exit) + ")"
+		}
+		if p.Branch != "" {
+			s += ", pushed " + p.Branch
+		}
+		return s
+	case *CrashPayload:
+		s := "crashed"
+		switch {
+		case p.ExitCode != nil:
+			s += fmt.Sprintf(" (exit %d)", *p.ExitCode)
+		case p.Signal != "":
+			s += " (" + p.Signal + ")"
+		}
+		return withDetail(s, p.Error, p.Reason)
+	case *KillPayload:
+		return withDetail("killed", p.Reason)
+	case *NudgePayload:
+		s := "nudged"
+		if who := firstNonEmpty(p.Target, p.Polecat); who != "" {
+			s += " " + who
+		}
+		return withDetail(s, p.Message)
+	case *MergePayload:
+		switch e.Type {
+		case "merge_started":
+			return strings.TrimSpace("merging " + p.Branch)
+		case "merged":
+			s := strings.TrimSpace("merged " + p.Branch)
+			if len(p.Commit) >= 7 {
+				s += " @ " + p.Commit[:7]
+			}
+			return s
+		default:
+			return withDetail(strings.TrimSpace("merge failed "+p.Branch), p.Reason, p.Error)
+		}
+	case *RawPayload:
+		if _, ok := eventSummaries[e.Type]; !ok {
+			if detail := rawSummary(p); detail != "" {
+				return e.Type + " " + detail
+			}
+		}
+	}
+
+	if e.Type == "sling" {
 		if e.TargetBead != "" && e.Message != "" {
 			return e.TargetBead + " → " + e.Message
 		}
@@ -112,6 +181,56 @@ func (e *Event) Summary() string {
 	return e.Type
 }
 
+// withDetail appends the first non-empty detail to a summary.
+func withDetail(summary string, details ...string) string {
+	if d := firstNonEmpty(details...); d != "" {
+		return summary + ": " + d
+	}
+	return summary
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// LatestSessionStarts returns the time of the most recent session_start
+// per session ID and per actor.
+func LatestSessionStarts(events []Event) map[string]time.Time {
+	starts := make(map[string]time.Time)
+	record := func(key string, ts time.Time) {
+		if key != "" && ts.After(starts[key]) {
+			starts[key] = ts
+		}
+	}
+	for _, e := range events {
+		if e.Type != "session_start" {
+			continue
+		}
+		record(e.SessionID, e.Timestamp)
+		record(e.Actor, e.Timestamp)
+	}
+	return starts
+}
+
+// Mentions returns true if the event is about a bead: it targets the bead
+// or carries its ID as a payload value.
+func (e *Event) Mentions(beadID string) bool {
+	if beadID == "" {
+		return false
+	}
+	if e.TargetBead == beadID {
+		return true
+	}
+	quoted, _ := json.Marshal(beadID)
+	return strings.Contains(string(e.Payload), string(quoted))
+}
+
 // TimeString returns a short time string.
 func (e *Event) TimeString() string {
 	return e.Timestamp.Format("15:04:05")
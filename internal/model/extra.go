@@ -0,0 +1,93 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extra holds JSON fields gastop doesn't model, so they survive a decode and
+// re-encode (e.g. in --json output) when gt/bd add new fields.
+type Extra map[string]json.RawMessage
+
+// knownFieldCache maps struct types to the JSON names of their fields.
+var knownFieldCache sync.Map
+
+// knownFields returns the lowercased JSON names of a struct's fields.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+	knownFieldCache.Store(t, fields)
+	return fields
+}
+
+// unknownFields returns the members of a JSON object that don't map to a
+// field of v's struct type. encoding/json matches names case-insensitively,
+// and so does this.
+func unknownFields(data []byte, v interface{}) (Extra, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	known := knownFields(reflect.TypeOf(v))
+	var extra Extra
+	for k, raw := range all {
+		if known[strings.ToLower(k)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(Extra)
+		}
+		extra[k] = raw
+	}
+	return extra, nil
+}
+
+// withExtra appends unknown fields to the encoded JSON object for v,
+// after the modeled fields. Names that v models are skipped.
+func withExtra(data []byte, extra Extra, v interface{}) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+	known := knownFields(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if !known[strings.ToLower(k)] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // Drop the closing brace
+	for _, k := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+// MergeState is the lifecycle state of a merge request in a rig's refinery.
+type MergeState string
+
+const (
+	MergeQueued   MergeState = "queued"
+	MergeInFlight MergeState = "in_flight"
+	MergeMerged   MergeState = "merged"
+	MergeFailed   MergeState = "failed"
+)
+
+// RepeatedFailureThreshold is how many failed attempts mark a merge as
+// repeatedly failing.
+const RepeatedFailureThreshold = 2
+
+// MergeRequest represents a branch submitted to a rig's merge queue.
+type MergeRequest struct {
+	ID            string     `json:"id"`
+	Rig           string     `json:"rig"`
+	Branch        string     `json:"branch"`
+	Bead          string     `json:"bead,omitempty"`
+	Polecat       string     `json:"polecat,omitempty"`
+	State         MergeState `json:"state"`
+	QueuedAt      time.Time  `json:"queued_at"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    time.Time  `json:"finished_at"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	Failures      int        `json:"failures"`
+}
+
+// Key returns the identity used to correlate queue entries and events.
+func (m *MergeRequest) Key() string {
+	if m.ID != "" {
+		return m.ID
+	}
+	if m.Branch != "" {
+		return m.Rig + "/" + m.Branch
+	}
+	return m.Rig + "/" + m.Bead
+}
+
+// TimeInQueue returns how long the request has been (or was) in the queue.
+func (m *MergeRequest) TimeInQueue() time.Duration {
+	start := m.QueuedAt
+	if start.IsZero() {
+		start = m.StartedAt
+	}
+	if start.IsZero() {
+		return 0
+	}
+	end := time.Now()
+	if !m.FinishedAt.IsZero() {
+		end = m.FinishedAt
+	}
+	return end.Sub(start)
+}
+
+// TimeInQueueString returns a human-readable time in queue.
+func (m *MergeRequest) TimeInQueueString() string {
+	d := m.TimeInQueue()
+	if d == 0 {
+		return ""
+	}
+	return HumanizeDuration(d)
+}
+
+// RepeatedlyFailing returns true if the merge has failed several times.
+func (m *MergeRequest) RepeatedlyFailing() bool {
+	return m.Failures >= RepeatedFailureThreshold
+}
+
+// StateIcon returns a state indicator character.
+func (m *MergeRequest) StateIcon() string {
+	switch m.State {
+	case MergeQueued:
+		return "⏳"
+	case MergeInFlight:
+		return "⚙"
+	case MergeMerged:
+		return "✓"
+	case MergeFailed:
+		return "✗"
+	default:
+		return "?"
+	}
+}
@@ -2,7 +2,11 @@ package model
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,7 +29,7 @@ func TestConvoyProgress(t *testing.T) {
 
 func TestConvoyStatusIcon(t *testing.T) {
 	tests := []struct {
-		status string
+		status BeadStatus
 		stuck  bool
 		want   string
 	}{
@@ -45,7 +49,7 @@ func TestConvoyStatusIcon(t *testing.T) {
 
 func TestBeadStatusIcon(t *testing.T) {
 	tests := []struct {
-		status string
+		status BeadStatus
 		stuck  bool
 		want   string
 	}{
@@ -77,7 +81,7 @@ func TestBeadAge(t *testing.T) {
 
 func TestPolecatStateIcon(t *testing.T) {
 	tests := []struct {
-		state string
+		state PolecatState
 		stuck bool
 		want  string
 	}{
@@ -158,3 +162,365 @@ func TestParseBeadFixture(t *testing.T) {
 		t.Errorf("expected blocked_by ['gt-002'], got %v", beads[2].BlockedBy)
 	}
 }
+
+func TestAttachSessionStarts(t *testing.T) {
+	early := time.Now().Add(-3 * time.Hour)
+	late := time.Now().Add(-2 * time.Hour)
+	events := []Event{
+		{Timestamp: early, Type: "session_start", Actor: "mayor", SessionID: "mayor-1"},
+		{Timestamp: late, Type: "session_start", Actor: "mayor", SessionID: "mayor-2"},
+		{Timestamp: early, Type: "session_start", Actor: "gastown/witness"},
+		{Timestamp: late, Type: "spawn", Actor: "deacon"},
+	}
+
+	agents := []Agent{
+		{Name: "mayor", Running: true, SessionID: "mayor-1"},
+		{Name: "witness", Rig: "gastown", Running: true},
+		{Name: "deacon", Running: true},
+	}
+	AttachSessionStarts(agents, events)
+
+	if !agents[0].StartedAt.Equal(early) {
+		t.Errorf("mayor StartedAt = %v, want session match %v", agents[0].StartedAt, early)
+	}
+	if agents[1].Uptime() != "3h" {
+		t.Errorf("witness uptime = %q, want 3h", agents[1].Uptime())
+	}
+	if !agents[2].StartedAt.IsZero() {
+		t.Error("expected no start time for deacon")
+	}
+}
+
+func TestBuildBeadTree(t *testing.T) {
+	beads := []Bead{
+		{ID: "gt-epic", IssueType: "epic", Status: "open", Children: []string{"gt-b"}},
+		{ID: "gt-a", Parent: "gt-epic", Status: "closed"},
+		{ID: "gt-b", Status: "in_progress"},
+		{ID: "gt-c", Parent: "gt-b", Status: "open", Stuck: true},
+		{ID: "gt-orphan", Parent: "gt-missing", Status: "open"},
+	}
+
+	roots := BuildBeadTree(beads)
+	if len(roots) != 2 || roots[0].Bead.ID != "gt-epic" || roots[1].Bead.ID != "gt-orphan" {
+		t.Fatalf("unexpected roots: %v", roots)
+	}
+
+	epic := roots[0]
+	if epic.Progress() != "1/3" {
+		t.Errorf("epic progress = %q, want 1/3", epic.Progress())
+	}
+	if !epic.AnyStuck() || epic.Stuck != 1 {
+		t.Errorf("expected epic to roll up 1 stuck descendant, got %d", epic.Stuck)
+	}
+	if len(epic.Children) != 2 || epic.Children[1].Children[0].Depth != 2 {
+		t.Errorf("expected gt-c nested at depth 2")
+	}
+
+	if got := len(Flatten(roots, nil)); got != 5 {
+		t.Errorf("expected 5 visible nodes, got %d", got)
+	}
+	if got := len(Flatten(roots, map[string]bool{"gt-epic": true})); got != 2 {
+		t.Errorf("expected 2 visible nodes with epic collapsed, got %d", got)
+	}
+}
+
+func TestMoleculeTimeline(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-4 * time.Hour)
+	closed1 := now.Add(-3 * time.Hour)
+	closed2 := now.Add(-2 * time.Hour)
+	mol := Molecule{Steps: []Bead{
+		{ID: "s1", Status: "closed", CreatedAt: created, ClosedAt: &closed1},
+		{ID: "s2", Status: "closed", CreatedAt: created, ClosedAt: &closed2},
+		{ID: "s3", Status: "in_progress", CreatedAt: created},
+		{ID: "s4", Status: "open", CreatedAt: created},
+	}}
+
+	if mol.Done() != 2 || mol.CurrentIndex() != 2 {
+		t.Fatalf("done=%d current=%d, want 2 and 2", mol.Done(), mol.CurrentIndex())
+	}
+
+	steps := mol.Timeline(now)
+	if steps[1].Duration != time.Hour {
+		t.Errorf("step 2 duration = %v, want 1h (starts when step 1 closed)", steps[1].Duration)
+	}
+	if !steps[2].Current || steps[2].Duration != 2*time.Hour {
+		t.Errorf("step 3 should be current with 2h elapsed, got %v", steps[2].Duration)
+	}
+	if mol.TypicalStepDuration() != time.Hour {
+		t.Errorf("typical = %v, want 1h", mol.TypicalStepDuration())
+	}
+
+	mol.AttachHolder([]Polecat{{Name: "Toast", Rig: "gastown", HookedBead: "s3"}})
+	if mol.Holder != "gastown/Toast" {
+		t.Errorf("holder = %q, want gastown/Toast", mol.Holder)
+	}
+}
+
+func TestDedupeConvoys(t *testing.T) {
+	convoys := []Convoy{
+		{ID: "hq-cv-1", Title: "Auth", TrackedIDs: []string{"gt-1", "gt-2"}},
+		{ID: "gt-cv-9", Title: "Docs"},
+		{ID: "hq-cv-1", Title: "Auth (bd)", DependencyCount: 2, Description: "from bd"},
+	}
+
+	deduped := DedupeConvoys(convoys)
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 convoys, got %d", len(deduped))
+	}
+	if deduped[0].Title != "Auth" || deduped[0].Description != "from bd" || len(deduped[0].TrackedIDs) != 2 {
+		t.Errorf("expected merged hq-cv-1, got %+v", deduped[0])
+	}
+}
+
+func TestConvoySwarm(t *testing.T) {
+	c := Convoy{ID: "hq-cv-1", TrackedIDs: []string{"gt-1", "gt-2"}}
+	polecats := []Polecat{
+		{Name: "Toast", HookedBead: "gt-1"},
+		{Name: "Furiosa", AssignedBead: "gt-2"},
+		{Name: "Nux", HookedBead: "gt-9"},
+	}
+
+	swarm := c.Swarm(polecats)
+	if len(swarm) != 2 || swarm[0].Name != "Toast" || swarm[1].Name != "Furiosa" {
+		t.Errorf("unexpected swarm: %v", swarm)
+	}
+}
+
+func TestEnumDecoding(t *testing.T) {
+	var b Bead
+	if err := json.Unmarshal([]byte(`{"id":"gt-1","status":"In Progress","issue_type":"EPIC"}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Status != "In Progress" || b.IssueType != "EPIC" {
+		t.Errorf("expected raw values to be kept, got status %q type %q", b.Status, b.IssueType)
+	}
+	if !b.Status.Is(StatusInProgress) || !b.IssueType.Is(TypeEpic) || !b.Status.IsActive() {
+		t.Errorf("expected %q %q to compare as in_progress epic", b.Status, b.IssueType)
+	}
+
+	for raw, want := range map[BeadStatus]BeadStatus{"wip": StatusInProgress, "Resolved": StatusClosed, "quarantined": "quarantined"} {
+		if got := raw.Canonical(); got != want {
+			t.Errorf("%q.Canonical() = %q, want %q", raw, got, want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"id":"gt-2","status":"Re-Opened"}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"status":"Re-Opened"`) {
+		t.Errorf("expected unknown status to pass through unchanged, got %s", out)
+	}
+
+	var p Polecat
+	if err := json.Unmarshal([]byte(`{"name":"Toast","state":"Idle"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if !p.State.Is(PolecatIdle) {
+		t.Errorf("state %q should compare as idle", p.State)
+	}
+}
+
+// compatExpect describes what a compat fixture directory should decode to.
+type compatExpect struct {
+	beadStatuses  []BeadStatus
+	beadTypes     []IssueType
+	convoyStatus  BeadStatus
+	polecatStates []PolecatState
+}
+
+func TestCompatFixtures(t *testing.T) {
+	versions := map[string]compatExpect{
+		"early": {
+			beadStatuses:  []BeadStatus{StatusInProgress, StatusClosed},
+			beadTypes:     []IssueType{TypeTask, TypeFeature},
+			convoyStatus:  StatusOpen,
+			polecatStates: []PolecatState{PolecatWorking},
+		},
+		"current": {
+			beadStatuses:  []BeadStatus{StatusHooked, StatusOpen, StatusTombstone},
+			beadTypes:     []IssueType{TypeTask, "gate", TypeBug},
+			convoyStatus:  StatusOpen,
+			polecatStates: []PolecatState{PolecatWorking, "zombie"},
+		},
+	}
+
+	for version, want := range versions {
+		t.Run(version, func(t *testing.T) {
+			dir := filepath.Join("../../tests/fixtures/compat", version)
+
+			var beads []Bead
+			decodeCompat(t, filepath.Join(dir, "beads.json"), &beads)
+			if len(beads) != len(want.beadStatuses) {
+				t.Fatalf("expected %d beads, got %d", len(want.beadStatuses), len(beads))
+			}
+			for i, b := range beads {
+				if b.Status.Canonical() != want.beadStatuses[i] || b.IssueType.Canonical() != want.beadTypes[i] {
+					t.Errorf("bead %s: status %q type %q, want %q %q",
+						b.ID, b.Status, b.IssueType, want.beadStatuses[i], want.beadTypes[i])
+				}
+				if b.StatusIcon() == "?" {
+					t.Errorf("bead %s: no icon for status %q", b.ID, b.Status)
+				}
+			}
+
+			var convoys []Convoy
+			decodeCompat(t, filepath.Join(dir, "convoys.json"), &convoys)
+			if convoys[0].Status.Canonical() != want.convoyStatus {
+				t.Errorf("convoy status = %q, want %q", convoys[0].Status, want.convoyStatus)
+			}
+
+			var polecats []Polecat
+			decodeCompat(t, filepath.Join(dir, "polecats.json"), &polecats)
+			for i, p := range polecats {
+				if p.State.Canonical() != want.polecatStates[i] {
+					t.Errorf("polecat %s: state %q, want %q", p.Name, p.State, want.polecatStates[i])
+				}
+			}
+		})
+	}
+}
+
+// decodeCompat decodes a fixture into v and checks that re-encoding keeps
+// every non-empty field of the input unchanged, including those the model
+// doesn't know. Empty modeled fields may be dropped by omitempty.
+func decodeCompat(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding %s: %v", path, err)
+	}
+	var in, round []map[string]interface{}
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(out, &round); err != nil {
+		t.Fatalf("re-decoding %s: %v", path, err)
+	}
+	for i := range in {
+		for k, val := range in[i] {
+			got, ok := round[i][k]
+			switch {
+			case !ok && !isEmptyJSON(val):
+				t.Errorf("%s[%d]: field %q lost on round trip", filepath.Base(path), i, k)
+			case ok && !reflect.DeepEqual(got, val):
+				t.Errorf("%s[%d]: field %q = %v after round trip, want %v", filepath.Base(path), i, k, got, val)
+			}
+		}
+	}
+}
+
+// isEmptyJSON returns true for JSON values omitempty would drop.
+func isEmptyJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func TestEventPayloads(t *testing.T) {
+	tests := []struct {
+		eventType string
+		payload   string
+		wantType  Payload
+		want      string
+	}{
+		{"session_start", `{"role":"polecat","cwd":"/town/gastown","pid":42}`, &SessionStartPayload{}, "session started as polecat in /town/gastown"},
+		{"crash", `{"exit_code":137,"error":"OOM"}`, &CrashPayload{}, "crashed (exit 137): OOM"},
+		{"done", `{"bead":"gt-1","branch":"polecat/Toast"}`, &DonePayload{}, "completed gt-1, pushed polecat/Toast"},
+		{"merge_failed", `{"branch":"polecat/Toast","reason":"conflict"}`, &MergePayload{}, "merge failed polecat/Toast: conflict"},
+		{"merged", `{"branch":"polecat/Toast","commit":"abcdef123"}`, &MergePayload{}, "merged polecat/Toast @ abcdef1"},
+		{"handoff", `{"to":"gastown/Furiosa","subject":"context full"}`, &HandoffPayload{}, "handed off to gastown/Furiosa: context full"},
+		{"escalation", `{"severity":"high","bead":"gt-2"}`, &RawPayload{}, "escalation bead=gt-2 severity=high"},
+		{"crash", `not json`, &RawPayload{}, "crashed"},
+	}
+
+	for _, tt := range tests {
+		e := Event{Type: tt.eventType, Payload: json.RawMessage(tt.payload)}
+		e.ParsePayload()
+		if fmt.Sprintf("%T", e.Data) != fmt.Sprintf("%T", tt.wantType) {
+			t.Errorf("%s: payload type %T, want %T", tt.eventType, e.Data, tt.wantType)
+		}
+		if got := e.Summary(); got != tt.want {
+			t.Errorf("%s: Summary() = %q, want %q", tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestEventMentions(t *testing.T) {
+	events := []Event{
+		{Type: "done", Payload: json.RawMessage(`{"bead":"gt-1"}`)},
+		{Type: "escalation", Payload: json.RawMessage(`{"issue":"gt-1","severity":"high"}`)},
+		{Type: "sling", Payload: json.RawMessage(`{"bead":"gt-12"}`)},
+		{Type: "nudge", Payload: json.RawMessage(`{"message":"see gt-1 please"}`)},
+	}
+	var got []string
+	for i := range events {
+		events[i].ParsePayload()
+		if events[i].Mentions("gt-1") {
+			got = append(got, events[i].Type)
+		}
+	}
+	if strings.Join(got, ",") != "done,escalation" {
+		t.Errorf("events mentioning gt-1 = %v, want done and escalation", got)
+	}
+	if events[0].Mentions("") {
+		t.Error("expected no event to mention an empty ID")
+	}
+}
+
+func TestPolecatTimeline(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	events := []Event{
+		{Timestamp: at(0), Type: "done", Actor: "gastown/polecats/Toast"}, // Previous Toast
+		{Timestamp: at(1), Type: "spawn", Payload: json.RawMessage(`{"polecat":"Toast","rig":"gastown"}`)},
+		{Timestamp: at(2), Type: "spawn", Payload: json.RawMessage(`{"polecat":"Toast","rig":"beads"}`)},
+		{Timestamp: at(3), Type: "sling", Payload: json.RawMessage(`{"bead":"gt-1","target":"gastown/polecats/Toast"}`)},
+		{Timestamp: at(4), Type: "nudge", Payload: json.RawMessage(`{"target":"gastown/Nux"}`)},
+		{Timestamp: at(33), Type: "nudge", Payload: json.RawMessage(`{"target":"gastown/Toast","message":"status?"}`)},
+		{Timestamp: at(40), Type: "handoff", Actor: "gastown/Nux", Payload: json.RawMessage(`{"to":"gastown/polecats/Toast"}`)},
+		{Timestamp: at(50), Type: "crash", Actor: "gastown/polecats/Toast"},
+		{Timestamp: at(90), Type: "done", Actor: "gastown/polecats/Toast", Payload: json.RawMessage(`{"bead":"gt-1"}`)},
+	}
+	for i := range events {
+		events[i].ParsePayload()
+	}
+
+	pc := Polecat{Name: "Toast", Rig: "gastown"}
+	timeline := pc.Timeline(events)
+	var types []string
+	for _, entry := range timeline {
+		types = append(types, entry.Event.Type)
+	}
+	if got := strings.Join(types, ","); got != "spawn,sling,nudge,handoff,crash,done" {
+		t.Fatalf("timeline = %q, want spawn,sling,nudge,handoff,crash,done", got)
+	}
+	if timeline[0].GapString() != "" || timeline[2].Gap != 30*time.Minute || timeline[5].GapString() != "40m" {
+		t.Errorf("unexpected gaps %v, %v, %q", timeline[0].Gap, timeline[2].Gap, timeline[5].GapString())
+	}
+
+	if got := (&Polecat{Name: "Ace", Rig: "gastown"}).Timeline(events); len(got) != 0 {
+		t.Errorf("expected no events for Ace, got %d", len(got))
+	}
+}
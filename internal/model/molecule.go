@@ -0,0 +1,145 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Molecule is a multi-step workflow bead. Its steps are child beads that
+// run in order.
+type Molecule struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Steps []Bead `json:"steps"`
+
+	// Polecat holding the current step (populated separately)
+	Holder string `json:"-"`
+}
+
+// MoleculeStep is a step with its timing derived from its neighbours.
+type MoleculeStep struct {
+	Bead
+	StartedAt time.Time
+	Duration  time.Duration // Elapsed so far for the current step
+	Current   bool
+}
+
+// Done returns the number of closed steps.
+func (m *Molecule) Done() int {
+	done := 0
+	for _, s := range m.Steps {
+		if s.Status.IsDone() {
+			done++
+		}
+	}
+	return done
+}
+
+// Progress returns completion as 0.0-1.0.
+func (m *Molecule) Progress() float64 {
+	if len(m.Steps) == 0 {
+		return 0
+	}
+	return float64(m.Done()) / float64(len(m.Steps))
+}
+
+// CurrentIndex returns the index of the step being worked: the first
+// in_progress step, else the first unfinished one. Returns -1 when all
+// steps are closed.
+func (m *Molecule) CurrentIndex() int {
+	first := -1
+	for i, s := range m.Steps {
+		if s.Status.IsActive() {
+			return i
+		}
+		if first < 0 && !s.Status.IsDone() {
+			first = i
+		}
+	}
+	return first
+}
+
+// Current returns the step being worked, or nil if the molecule is done.
+func (m *Molecule) Current() *Bead {
+	if i := m.CurrentIndex(); i >= 0 {
+		return &m.Steps[i]
+	}
+	return nil
+}
+
+// Timeline returns the steps with start times and durations. A step starts
+// when it was created or when the previous step closed, whichever is later.
+func (m *Molecule) Timeline(now time.Time) []MoleculeStep {
+	current := m.CurrentIndex()
+	steps := make([]MoleculeStep, len(m.Steps))
+	var prevClosed time.Time
+	for i, b := range m.Steps {
+		start := b.CreatedAt
+		if prevClosed.After(start) {
+			start = prevClosed
+		}
+		step := MoleculeStep{Bead: b, StartedAt: start, Current: i == current}
+		switch {
+		case b.ClosedAt != nil:
+			step.Duration = b.ClosedAt.Sub(start)
+			prevClosed = *b.ClosedAt
+		case step.Current && b.Status.IsActive():
+			step.Duration = now.Sub(start)
+		}
+		steps[i] = step
+	}
+	return steps
+}
+
+// TypicalStepDuration returns the median duration of closed steps, or 0
+// if none have closed yet.
+func (m *Molecule) TypicalStepDuration() time.Duration {
+	var durations []time.Duration
+	for _, s := range m.Timeline(time.Now()) {
+		if s.ClosedAt != nil && s.Duration > 0 {
+			durations = append(durations, s.Duration)
+		}
+	}
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[mid-1] + durations[mid]) / 2
+	}
+	return durations[mid]
+}
+
+// TypicalStepString returns the typical step duration, human-readable.
+func (m *Molecule) TypicalStepString() string {
+	if d := m.TypicalStepDuration(); d > 0 {
+		return HumanizeDuration(d)
+	}
+	return "-"
+}
+
+// DurationString returns a human-readable step duration.
+func (s *MoleculeStep) DurationString() string {
+	if s.Duration <= 0 {
+		return "-"
+	}
+	return HumanizeDuration(s.Duration)
+}
+
+// AttachHolder sets Holder to the polecat hooked to (or assigned) the
+// current step, falling back to the step's assignee.
+func (m *Molecule) AttachHolder(polecats []Polecat) {
+	m.Holder = ""
+	cur := m.Current()
+	if cur == nil {
+		return
+	}
+	for _, pc := range polecats {
+		if pc.HookedBead == cur.ID || pc.AssignedBead == cur.ID {
+			m.Holder = pc.FullName()
+			return
+		}
+	}
+	m.Holder = cur.Assignee
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Payload is the decoded, type-specific payload of an event.
+type Payload interface {
+	// Common returns the fields most payloads share.
+	Common() *PayloadCommon
+}
+
+// PayloadCommon holds the fields shared across event payloads.
+type PayloadCommon struct {
+	Rig       string `json:"rig,omitempty"`
+	Polecat   string `json:"polecat,omitempty"`
+	Bead      string `json:"bead,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Target    string `json:"target,omitempty"` // rig/polecat path for sling, nudge, handoff
+}
+
+// Common implements Payload.
+func (c *PayloadCommon) Common() *PayloadCommon {
+	return c
+}
+
+// SessionStartPayload is carried by session_start events.
+type SessionStartPayload struct {
+	PayloadCommon
+	Role  string `json:"role,omitempty"`
+	CWD   string `json:"cwd,omitempty"`
+	PID   int    `json:"pid,omitempty"`
+	Topic string `json:"topic,omitempty"`
+}
+
+// SpawnPayload is carried by spawn events.
+type SpawnPayload struct {
+	PayloadCommon
+	Branch string `json:"branch,omitempty"`
+}
+
+// HandoffPayload is carried by handoff events.
+type HandoffPayload struct {
+	PayloadCommon
+	To      string `json:"to,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// DonePayload is carried by done events. A branch means work was pushed
+// for the refinery to merge.
+type DonePayload struct {
+	PayloadCommon
+	Branch string `json:"branch,omitempty"`
+	MR     string `json:"mr,omitempty"`
+	Exit   string `json:"exit,omitempty"` // COMPLETED, ESCALATED, DEFERRED
+}
+
+// CrashPayload is carried by crash events.
+type CrashPayload struct {
+	PayloadCommon
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Signal   string `json:"signal,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// KillPayload is carried by kill events.
+type KillPayload struct {
+	PayloadCommon
+	Reason string `json:"reason,omitempty"`
+}
+
+// NudgePayload is carried by nudge and polecat_nudged events.
+type NudgePayload struct {
+	PayloadCommon
+	Message string `json:"message,omitempty"`
+}
+
+// MergePayload is carried by merge_started, merged and merge_failed events.
+type MergePayload struct {
+	PayloadCommon
+	Branch string `json:"branch,omitempty"`
+	MR     string `json:"mr,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RawPayload is the fallback for event types without a registered payload.
+type RawPayload struct {
+	PayloadCommon
+	Fields map[string]interface{} `json:"-"`
+}
+
+// payloadTypes maps event types to constructors for their payloads. It is
+// fixed at build time, so refreshes can decode concurrently without a lock.
+var payloadTypes = map[string]func() Payload{
+	"session_start":  func() Payload { return &SessionStartPayload{} },
+	"spawn":          func() Payload { return &SpawnPayload{} },
+	"sling":          func() Payload { return &PayloadCommon{} },
+	"handoff":        func() Payload { return &HandoffPayload{} },
+	"done":           func() Payload { return &DonePayload{} },
+	"crash":          func() Payload { return &CrashPayload{} },
+	"kill":           func() Payload { return &KillPayload{} },
+	"nudge":          func() Payload { return &NudgePayload{} },
+	"polecat_nudged": func() Payload { return &NudgePayload{} },
+	"merge_started":  func() Payload { return &MergePayload{} },
+	"merged":         func() Payload { return &MergePayload{} },
+	"merge_failed":   func() Payload { return &MergePayload{} },
+}
+
+// DecodePayload decodes a raw payload into the type registered for the
+// event type, or a RawPayload. It never returns nil; malformed payloads
+// decode to an empty RawPayload.
+func DecodePayload(eventType string, raw json.RawMessage) Payload {
+	if factory, ok := payloadTypes[eventType]; ok {
+		p := factory()
+		if len(raw) == 0 || json.Unmarshal(raw, p) == nil {
+			return p
+		}
+	}
+
+	p := &RawPayload{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &p.PayloadCommon)
+		_ = json.Unmarshal(raw, &p.Fields)
+	}
+	return p
+}
+
+// FormatPayload returns the payload as indented JSON with sorted keys, for
+// display.
+func FormatPayload(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return []string{string(raw)}
+	}
+	// Marshalling a map sorts its keys
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return []string{string(raw)}
+	}
+	return strings.Split(string(out), "\n")
+}
+
+// rawSummary describes a payload of an unregistered event type, listing
+// its string fields in key order.
+func rawSummary(p *RawPayload) string {
+	var keys []string
+	for k, v := range p.Fields {
+		if _, ok := v.(string); ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, p.Fields[k]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,32 +1,61 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Polecat represents a worker agent in Gas Town.
 type Polecat struct {
-	Name         string    `json:"name"`
-	Rig          string    `json:"rig"`
-	State        string    `json:"state"` // working, done, stuck, idle
-	AssignedBead string    `json:"assigned_bead,omitempty"`
-	SessionID    string    `json:"session_id,omitempty"`
-	Running      bool      `json:"session_running"`
-	Attached     bool      `json:"attached"`
-	CreatedAt    time.Time `json:"created_at"`
-	LastActivity time.Time `json:"last_activity"`
-	Branch       string    `json:"branch,omitempty"`
-	ClonePath    string    `json:"clone_path,omitempty"`
-	Windows      int       `json:"windows,omitempty"`
+	Name         string       `json:"name"`
+	Rig          string       `json:"rig"`
+	State        PolecatState `json:"state"` // working, done, stuck, idle
+	AssignedBead string       `json:"assigned_bead,omitempty"`
+	SessionID    string       `json:"session_id,omitempty"`
+	Running      bool         `json:"session_running"`
+	Attached     bool         `json:"attached"`
+	CreatedAt    time.Time    `json:"created_at"`
+	LastActivity time.Time    `json:"last_activity"`
+	Branch       string       `json:"branch,omitempty"`
+	ClonePath    string       `json:"clone_path,omitempty"`
+	Windows      int          `json:"windows,omitempty"`
 
 	// Hooked work info (populated separately)
 	HookedBead  string `json:"-"`
 	HookedTitle string `json:"-"`
 
 	// Computed fields
-	Stuck       bool   `json:"-"`
-	StuckReason string `json:"-"`
+	Stuck       bool      `json:"-"`
+	StuckReason string    `json:"-"`
+	StuckLevel  Severity  `json:"-"`
+	StuckSince  time.Time `json:"-"` // Start of the current stuck episode
+	Snoozed     bool      `json:"-"` // Acknowledged or snoozed by the user
+
+	// Fields from newer gt versions, kept for JSON output
+	Extra Extra `json:"-"`
+}
+
+// UnmarshalJSON decodes a polecat, keeping unknown fields in Extra.
+func (p *Polecat) UnmarshalJSON(data []byte) error {
+	type plain Polecat
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, plain{})
+	p.Extra = extra
+	return err
+}
+
+// MarshalJSON encodes a polecat, including any unknown fields.
+func (p Polecat) MarshalJSON() ([]byte, error) {
+	type plain Polecat
+	data, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	return withExtra(data, p.Extra, plain{})
 }
 
 // FullName returns the rig/name format.
@@ -39,14 +68,14 @@ func (p *Polecat) StateIcon() string {
 	if p.Stuck {
 		return "⚠"
 	}
-	switch p.State {
-	case "working":
+	switch p.State.Canonical() {
+	case PolecatWorking:
 		return "●"
-	case "done":
+	case PolecatDone:
 		return "✓"
-	case "stuck":
+	case PolecatStuck:
 		return "⚠"
-	case "idle":
+	case PolecatIdle:
 		return "○"
 	default:
 		return "?"
@@ -90,6 +119,15 @@ func (p *Polecat) ActivityAgo() string {
 	return fmt.Sprintf("%dd", days)
 }
 
+// CurrentBead returns the bead the polecat is working on: the hooked bead,
+// else the assigned one.
+func (p *Polecat) CurrentBead() string {
+	if p.HookedBead != "" {
+		return p.HookedBead
+	}
+	return p.AssignedBead
+}
+
 // WorkDescription returns a short description of current work.
 func (p *Polecat) WorkDescription() string {
 	if p.HookedBead != "" {
@@ -117,14 +155,117 @@ func (p *Polecat) WorkDescription() string {
 	return ""
 }
 
+// TimelineEntry is one event in a polecat's lifecycle.
+type TimelineEntry struct {
+	Event Event
+	Gap   time.Duration // Since the previous entry; zero for the first
+}
+
+// GapString returns the time since the previous entry, or "" for the first.
+func (t *TimelineEntry) GapString() string {
+	if t.Gap <= 0 {
+		return ""
+	}
+	return HumanizeDuration(t.Gap)
+}
+
+// Involves returns true if the event is about the polecat: it names the
+// polecat in its payload, targets it, hands off to it, or was emitted by it.
+func (p *Polecat) Involves(e *Event) bool {
+	full := p.FullName()
+	c := e.PayloadData().Common()
+	if c.Polecat == p.Name && (c.Rig == "" || c.Rig == p.Rig) {
+		return true
+	}
+	if c.Target != "" && polecatPath(c.Target) == full {
+		return true
+	}
+	if h, ok := e.PayloadData().(*HandoffPayload); ok && h.To != "" && polecatPath(h.To) == full {
+		return true
+	}
+	return polecatPath(e.Actor) == full
+}
+
+// Timeline returns the polecat's events since its latest spawn, oldest
+// first, from an oldest-first event log. Names are reused once a polecat
+// is nuked, so events before the spawn belong to an earlier polecat.
+func (p *Polecat) Timeline(events []Event) []TimelineEntry {
+	var involved []*Event
+	for i := range events {
+		if !p.Involves(&events[i]) {
+			continue
+		}
+		if events[i].Type == "spawn" {
+			involved = involved[:0]
+		}
+		involved = append(involved, &events[i])
+	}
+
+	timeline := make([]TimelineEntry, len(involved))
+	for i, e := range involved {
+		timeline[i].Event = *e
+		if i > 0 {
+			timeline[i].Gap = e.Timestamp.Sub(involved[i-1].Timestamp)
+		}
+	}
+	return timeline
+}
+
+// polecatPath maps "gastown/polecats/Toast" to "gastown/Toast".
+func polecatPath(name string) string {
+	return strings.Replace(name, "/polecats/", "/", 1)
+}
+
 // Agent represents a broader category of agents (witness, refinery, crew).
 type Agent struct {
 	Name      string `json:"name"`
-	Role      string `json:"role"` // witness, refinery, crew, polecat
+	Role      string `json:"role"` // mayor, deacon, witness, refinery, crew, polecat
 	Rig       string `json:"rig"`
 	State     string `json:"state"`
 	Running   bool   `json:"running"`
 	SessionID string `json:"session_id,omitempty"`
+
+	// Computed fields
+	Required  bool      `json:"-"` // Town infrastructure that should always run
+	StartedAt time.Time `json:"-"` // From the latest session_start event
+}
+
+// IsDown returns true if a required agent is not running.
+func (a *Agent) IsDown() bool {
+	return a.Required && !a.Running
+}
+
+// Uptime returns a human-readable session uptime, if known.
+func (a *Agent) Uptime() string {
+	if !a.Running || a.StartedAt.IsZero() {
+		return ""
+	}
+	return HumanizeDuration(time.Since(a.StartedAt))
+}
+
+// StateIcon returns a running indicator character.
+func (a *Agent) StateIcon() string {
+	if a.IsDown() {
+		return "✗"
+	}
+	if a.Running {
+		return "●"
+	}
+	return "○"
+}
+
+// AttachSessionStarts sets StartedAt on each agent from session_start
+// events, matching by session ID or by actor name.
+func AttachSessionStarts(agents []Agent, events []Event) {
+	starts := LatestSessionStarts(events)
+	for i := range agents {
+		ag := &agents[i]
+		if ts, ok := starts[ag.SessionID]; ok && ag.SessionID != "" {
+			ag.StartedAt = ts
+		} else if ts, ok := starts[ag.FullName()]; ok {
+			ag.StartedAt = ts
+		}
+	}
 }
 
 // FullName returns the rig/name format.
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// BeadStatus is the workflow status of a bead (or convoy), as reported by
+// bd. The raw value is kept, so --json output passes new or unusual
+// spellings through unchanged; compare with Is or Canonical.
+type BeadStatus string
+
+const (
+	StatusOpen       BeadStatus = "open"
+	StatusInProgress BeadStatus = "in_progress"
+	StatusHooked     BeadStatus = "hooked"
+	StatusBlocked    BeadStatus = "blocked"
+	StatusDeferred   BeadStatus = "deferred"
+	StatusClosed     BeadStatus = "closed"
+	StatusTombstone  BeadStatus = "tombstone"
+)
+
+// statusAliases maps spellings seen across bd versions to canonical values.
+var statusAliases = map[string]BeadStatus{
+	"inprogress": StatusInProgress,
+	"wip":        StatusInProgress,
+	"done":       StatusClosed,
+	"resolved":   StatusClosed,
+}
+
+// Canonical returns the status with case, separators and known aliases
+// normalized, so "In-Progress" and "wip" both become "in_progress".
+// Unknown values are normalized the same way but otherwise kept.
+func (s BeadStatus) Canonical() BeadStatus {
+	norm := normalizeEnum(string(s))
+	if alias, ok := statusAliases[norm]; ok {
+		return alias
+	}
+	return BeadStatus(norm)
+}
+
+// Is returns true if the status is want, in any spelling.
+func (s BeadStatus) Is(want BeadStatus) bool {
+	return s.Canonical() == want
+}
+
+// IsDone returns true for statuses where no work remains.
+func (s BeadStatus) IsDone() bool {
+	c := s.Canonical()
+	return c == StatusClosed || c == StatusTombstone
+}
+
+// IsActive returns true for statuses where someone is working the bead.
+func (s BeadStatus) IsActive() bool {
+	c := s.Canonical()
+	return c == StatusInProgress || c == StatusHooked
+}
+
+// IssueType is the kind of bead: bug, feature, task, epic, molecule, etc.
+// Like BeadStatus, the raw value is kept.
+type IssueType string
+
+const (
+	TypeBug      IssueType = "bug"
+	TypeFeature  IssueType = "feature"
+	TypeTask     IssueType = "task"
+	TypeChore    IssueType = "chore"
+	TypeEpic     IssueType = "epic"
+	TypeMolecule IssueType = "molecule"
+	TypeAgent    IssueType = "agent"
+	TypeConvoy   IssueType = "convoy"
+)
+
+// Canonical returns the type with case and separators normalized.
+func (t IssueType) Canonical() IssueType {
+	return IssueType(normalizeEnum(string(t)))
+}
+
+// Is returns true if the type is want, in any spelling.
+func (t IssueType) Is(want IssueType) bool {
+	return t.Canonical() == want
+}
+
+// PolecatState is the lifecycle state of a polecat, as reported by gt.
+// Like BeadStatus, the raw value is kept.
+type PolecatState string
+
+const (
+	PolecatWorking PolecatState = "working"
+	PolecatDone    PolecatState = "done"
+	PolecatStuck   PolecatState = "stuck"
+	PolecatIdle    PolecatState = "idle"
+)
+
+// Canonical returns the state with case and separators normalized.
+func (s PolecatState) Canonical() PolecatState {
+	return PolecatState(normalizeEnum(string(s)))
+}
+
+// Is returns true if the state is want, in any spelling.
+func (s PolecatState) Is(want PolecatState) bool {
+	return s.Canonical() == want
+}
+
+// normalizeEnum lowercases and converts separators to underscores, so
+// "In-Progress" and "in progress" both become "in_progress".
+func normalizeEnum(raw string) string {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(s)
+}
+
+// Severity ranks how urgently stuck work needs attention.
+type Severity string
+
+const (
+	SeverityNone     Severity = ""
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Rank orders severities for comparison: none < warning < critical.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2
+	}
+	return 0
+}
+
+// StuckFor returns how long an item has been stuck given the start of its
+// stuck episode, or "" if unknown.
+func StuckFor(since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return HumanizeDuration(time.Since(since))
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BeadNode is a bead in the epic/molecule hierarchy, with progress and
+// stuck status rolled up from its descendants.
+type BeadNode struct {
+	Bead     Bead
+	Children []*BeadNode
+	Depth    int
+
+	// Rolled up over all descendants (not the bead itself)
+	Closed int
+	Total  int
+	Stuck  int // Not counting snoozed beads
+}
+
+// HasChildren returns true if the node has any child beads.
+func (n *BeadNode) HasChildren() bool {
+	return len(n.Children) > 0
+}
+
+// Progress returns the rolled-up progress as "closed/total".
+func (n *BeadNode) Progress() string {
+	return fmt.Sprintf("%d/%d", n.Closed, n.Total)
+}
+
+// AnyStuck returns true if the bead or any descendant is stuck.
+func (n *BeadNode) AnyStuck() bool {
+	return n.Bead.Stuck || n.Stuck > 0
+}
+
+// BuildBeadTree groups beads under their parents. Links come from each
+// child's Parent and each parent's Children; beads whose parent isn't in
+// the list are roots. Input order is preserved among siblings.
+func BuildBeadTree(beads []Bead) []*BeadNode {
+	nodes := make(map[string]*BeadNode, len(beads))
+	order := make(map[string]int, len(beads))
+	for i, b := range beads {
+		nodes[b.ID] = &BeadNode{Bead: b}
+		order[b.ID] = i
+	}
+
+	parentOf := make(map[string]string)
+	for _, b := range beads {
+		for _, child := range b.Children {
+			if _, ok := nodes[child]; ok && child != b.ID {
+				parentOf[child] = b.ID
+			}
+		}
+	}
+	for _, b := range beads {
+		if _, ok := nodes[b.Parent]; ok && b.Parent != b.ID {
+			parentOf[b.ID] = b.Parent
+		}
+	}
+
+	// Break parent cycles so every bead is reachable from a root
+	for id := range parentOf {
+		seen := map[string]bool{id: true}
+		for p, ok := parentOf[id]; ok; p, ok = parentOf[p] {
+			if seen[p] {
+				delete(parentOf, p)
+				break
+			}
+			seen[p] = true
+		}
+	}
+
+	var roots []*BeadNode
+	for _, b := range beads {
+		n := nodes[b.ID]
+		if p, ok := parentOf[b.ID]; ok {
+			nodes[p].Children = append(nodes[p].Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	for _, n := range roots {
+		n.rollUp(0, order)
+	}
+	return roots
+}
+
+// rollUp sets depths and sums descendant counts.
+func (n *BeadNode) rollUp(depth int, order map[string]int) {
+	n.Depth = depth
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		return order[n.Children[i].Bead.ID] < order[n.Children[j].Bead.ID]
+	})
+	n.Closed, n.Total, n.Stuck = 0, 0, 0
+	for _, c := range n.Children {
+		c.rollUp(depth+1, order)
+		n.Total += 1 + c.Total
+		n.Closed += c.Closed
+		if c.Bead.Status.IsDone() {
+			n.Closed++
+		}
+		n.Stuck += c.Stuck
+		if c.Bead.Stuck && !c.Bead.Snoozed {
+			n.Stuck++
+		}
+	}
+}
+
+// Flatten returns the visible nodes in display order, skipping the
+// descendants of collapsed nodes.
+func Flatten(roots []*BeadNode, collapsed map[string]bool) []*BeadNode {
+	var out []*BeadNode
+	var walk func(nodes []*BeadNode)
+	walk = func(nodes []*BeadNode) {
+		for _, n := range nodes {
+			out = append(out, n)
+			if !collapsed[n.Bead.ID] {
+				walk(n.Children)
+			}
+		}
+	}
+	walk(roots)
+	return out
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorktreeStatus summarizes the git state of a polecat's clone relative to
+// the rig's default branch.
+type WorktreeStatus struct {
+	Path         string    `json:"path"`
+	Branch       string    `json:"branch"`
+	BaseBranch   string    `json:"base_branch"` // e.g. origin/main
+	Ahead        int       `json:"ahead"`
+	Behind       int       `json:"behind"`
+	Uncommitted  int       `json:"uncommitted"` // Files with uncommitted changes
+	FilesChanged int       `json:"files_changed"`
+	Insertions   int       `json:"insertions"`
+	Deletions    int       `json:"deletions"`
+	LastCommit   string    `json:"last_commit,omitempty"`
+	LastCommitAt time.Time `json:"last_commit_at"`
+	HasConflicts bool      `json:"has_conflicts"`
+}
+
+// DiffStat returns a short diffstat string like "3 files +120 -4".
+func (w *WorktreeStatus) DiffStat() string {
+	if w.FilesChanged == 0 {
+		return "no changes"
+	}
+	files := "files"
+	if w.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s +%d -%d", w.FilesChanged, files, w.Insertions, w.Deletions)
+}
+
+// LastCommitAgo returns a human-readable age of the last commit.
+func (w *WorktreeStatus) LastCommitAgo() string {
+	if w.LastCommitAt.IsZero() {
+		return ""
+	}
+	return HumanizeDuration(time.Since(w.LastCommitAt))
+}
+
+// IsDirty returns true if the worktree has uncommitted changes.
+func (w *WorktreeStatus) IsDirty() bool {
+	return w.Uncommitted > 0
+}
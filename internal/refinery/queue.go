@@ -0,0 +1,219 @@
+// Package refinery reconstructs per-rig merge queues from the event log and
+// the refinery's own queue listing.
+package refinery
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Queue is the merge queue for a single rig.
+type Queue struct {
+	Rig      string
+	Requests []*model.MergeRequest
+}
+
+// Counts returns the number of requests in each state.
+func (q *Queue) Counts() map[model.MergeState]int {
+	counts := make(map[model.MergeState]int)
+	for _, mr := range q.Requests {
+		counts[mr.State]++
+	}
+	return counts
+}
+
+// Alerts returns requests that have failed repeatedly and are not merged.
+func (q *Queue) Alerts() []*model.MergeRequest {
+	var alerts []*model.MergeRequest
+	for _, mr := range q.Requests {
+		if mr.State != model.MergeMerged && mr.RepeatedlyFailing() {
+			alerts = append(alerts, mr)
+		}
+	}
+	return alerts
+}
+
+// mergeFields extracts what the queue needs from a merge-related event's
+// typed payload. It returns false if the payload was malformed.
+func mergeFields(e *model.Event) (c model.PayloadCommon, branch, mr, reason string, ok bool) {
+	switch p := e.PayloadData().(type) {
+	case *model.DonePayload:
+		return p.PayloadCommon, p.Branch, p.MR, "", true
+	case *model.MergePayload:
+		reason = p.Reason
+		if reason == "" {
+			reason = p.Error
+		}
+		return p.PayloadCommon, p.Branch, p.MR, reason, true
+	}
+	return c, "", "", "", false
+}
+
+// Build derives merge queues from events (oldest first) and the entries
+// currently listed by the refinery. Listed entries are authoritative for
+// what is queued right now; events supply history and failure reasons.
+func Build(events []model.Event, listed []model.MergeRequest) []Queue {
+	byKey := make(map[string]*model.MergeRequest)
+	byBranch := make(map[string]*model.MergeRequest)
+	var order []string
+
+	// get finds the request by MR ID, falling back to rig/branch when
+	// either side has no ID yet (e.g. a done event that predates the MR).
+	get := func(mr model.MergeRequest) *model.MergeRequest {
+		if mr.ID != "" {
+			if existing, ok := byKey[mr.ID]; ok {
+				return existing
+			}
+		}
+		branchKey := ""
+		if mr.Branch != "" {
+			branchKey = mr.Rig + "/" + mr.Branch
+			if existing, ok := byBranch[branchKey]; ok && (mr.ID == "" || existing.ID == "") {
+				if existing.ID == "" && mr.ID != "" {
+					existing.ID = mr.ID
+					byKey[mr.ID] = existing
+				}
+				return existing
+			}
+		}
+		key := mr.Key()
+		if existing, ok := byKey[key]; ok {
+			return existing
+		}
+		byKey[key] = &mr
+		order = append(order, key)
+		if branchKey != "" {
+			byBranch[branchKey] = &mr
+		}
+		return &mr
+	}
+
+	for _, e := range events {
+		if !isMergeEvent(e.Type) {
+			continue
+		}
+		p, branch, id, reason, ok := mergeFields(&e)
+		// done events only enter the queue if they submitted a branch
+		if !ok || (e.Type == "done" && branch == "") {
+			continue
+		}
+		if p.Rig == "" {
+			p.Rig = rigFromActor(e.Actor)
+		}
+
+		mr := get(model.MergeRequest{ID: id, Rig: p.Rig, Branch: branch, Bead: p.Bead, Polecat: p.Polecat})
+		if mr.Bead == "" {
+			mr.Bead = p.Bead
+		}
+		if mr.Polecat == "" {
+			mr.Polecat = p.Polecat
+		}
+
+		switch e.Type {
+		case "done":
+			mr.State = model.MergeQueued
+			mr.QueuedAt = e.Timestamp
+			mr.FinishedAt = time.Time{}
+		case "merge_started":
+			mr.State = model.MergeInFlight
+			mr.StartedAt = e.Timestamp
+			if mr.QueuedAt.IsZero() {
+				mr.QueuedAt = e.Timestamp
+			}
+		case "merged":
+			mr.State = model.MergeMerged
+			mr.FinishedAt = e.Timestamp
+		case "merge_failed":
+			mr.State = model.MergeFailed
+			mr.FinishedAt = e.Timestamp
+			mr.Failures++
+			mr.FailureReason = reason
+		}
+	}
+
+	for _, listedMR := range listed {
+		mr := get(listedMR)
+		if state := normalizeState(string(listedMR.State)); state != "" {
+			mr.State = state
+		} else if mr.State == "" || mr.State == model.MergeMerged || mr.State == model.MergeFailed {
+			// Re-submitted after a failure (or first seen via the listing)
+			mr.State = model.MergeQueued
+		}
+		if mr.QueuedAt.IsZero() {
+			mr.QueuedAt = listedMR.QueuedAt
+		}
+		if listedMR.Failures > mr.Failures {
+			mr.Failures = listedMR.Failures
+		}
+	}
+
+	queues := make(map[string]*Queue)
+	for _, key := range order {
+		mr := byKey[key]
+		q, ok := queues[mr.Rig]
+		if !ok {
+			q = &Queue{Rig: mr.Rig}
+			queues[mr.Rig] = q
+		}
+		q.Requests = append(q.Requests, mr)
+	}
+
+	result := make([]Queue, 0, len(queues))
+	for _, q := range queues {
+		sort.SliceStable(q.Requests, func(i, j int) bool {
+			return stateOrder(q.Requests[i].State) < stateOrder(q.Requests[j].State)
+		})
+		result = append(result, *q)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Rig < result[j].Rig })
+	return result
+}
+
+// isMergeEvent returns true for events that move a request through the queue.
+func isMergeEvent(eventType string) bool {
+	switch eventType {
+	case "done", "merge_started", "merged", "merge_failed":
+		return true
+	}
+	return false
+}
+
+// normalizeState maps refinery queue states onto MergeState.
+func normalizeState(state string) model.MergeState {
+	switch strings.ToLower(state) {
+	case "queued", "pending", "ready":
+		return model.MergeQueued
+	case "in_flight", "processing", "in_progress", "merging":
+		return model.MergeInFlight
+	case "merged":
+		return model.MergeMerged
+	case "failed":
+		return model.MergeFailed
+	}
+	return ""
+}
+
+// rigFromActor extracts the rig from an actor like "gastown/refinery".
+func rigFromActor(actor string) string {
+	if rig, _, ok := strings.Cut(actor, "/"); ok {
+		return rig
+	}
+	return ""
+}
+
+// stateOrder sorts failed and in-flight work ahead of queued and merged.
+func stateOrder(state model.MergeState) int {
+	switch state {
+	case model.MergeFailed:
+		return 0
+	case model.MergeInFlight:
+		return 1
+	case model.MergeQueued:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,134 @@
+package refinery
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+func event(t *testing.T, ts time.Time, eventType, actor string, payload map[string]string) model.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.Event{Timestamp: ts, Type: eventType, Actor: actor, Payload: data}
+}
+
+func TestBuildFromEvents(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	at := func(mins int) time.Time { return base.Add(time.Duration(mins) * time.Minute) }
+
+	events := []model.Event{
+		event(t, at(0), "done", "gastown/Toast", map[string]string{"rig": "gastown", "branch": "polecat/toast", "bead": "gt-001"}),
+		event(t, at(1), "done", "gastown/Nux", map[string]string{"rig": "gastown", "branch": "polecat/nux"}),
+		event(t, at(2), "merge_started", "gastown/refinery", map[string]string{"branch": "polecat/toast"}),
+		event(t, at(5), "merged", "gastown/refinery", map[string]string{"branch": "polecat/toast"}),
+		event(t, at(6), "merge_started", "gastown/refinery", map[string]string{"branch": "polecat/nux"}),
+		event(t, at(7), "merge_failed", "gastown/refinery", map[string]string{"branch": "polecat/nux", "reason": "conflict in main.go"}),
+		event(t, at(8), "merge_started", "gastown/refinery", map[string]string{"branch": "polecat/nux"}),
+		event(t, at(9), "merge_failed", "gastown/refinery", map[string]string{"branch": "polecat/nux", "error": "tests failed"}),
+		event(t, at(10), "done", "beads/Max", map[string]string{"rig": "beads"}), // no branch, ignored
+		event(t, at(11), "spawn", "mayor", map[string]string{"rig": "gastown"}),
+	}
+
+	queues := Build(events, nil)
+	if len(queues) != 1 {
+		t.Fatalf("expected 1 queue, got %d", len(queues))
+	}
+	q := queues[0]
+	if q.Rig != "gastown" {
+		t.Errorf("Rig = %s, want gastown", q.Rig)
+	}
+	if len(q.Requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(q.Requests))
+	}
+
+	// Failed requests sort first
+	failed := q.Requests[0]
+	if failed.Branch != "polecat/nux" || failed.State != model.MergeFailed {
+		t.Errorf("first request = %s (%s), want failed polecat/nux", failed.Branch, failed.State)
+	}
+	if failed.Failures != 2 {
+		t.Errorf("Failures = %d, want 2", failed.Failures)
+	}
+	if failed.FailureReason != "tests failed" {
+		t.Errorf("FailureReason = %q, want 'tests failed'", failed.FailureReason)
+	}
+	if got := failed.TimeInQueue(); got != 8*time.Minute {
+		t.Errorf("TimeInQueue = %v, want 8m", got)
+	}
+
+	merged := q.Requests[1]
+	if merged.State != model.MergeMerged || merged.Bead != "gt-001" {
+		t.Errorf("second request = %s/%s, want merged gt-001", merged.State, merged.Bead)
+	}
+
+	if alerts := q.Alerts(); len(alerts) != 1 || alerts[0] != failed {
+		t.Errorf("expected one repeated-failure alert, got %v", alerts)
+	}
+	counts := q.Counts()
+	if counts[model.MergeFailed] != 1 || counts[model.MergeMerged] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestBuildWithListedQueue(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	events := []model.Event{
+		event(t, base, "merge_failed", "gastown/refinery", map[string]string{"branch": "polecat/nux", "reason": "conflict"}),
+	}
+	listed := []model.MergeRequest{
+		{Rig: "gastown", Branch: "polecat/nux"},
+		{Rig: "gastown", Branch: "polecat/furiosa", State: "processing"},
+		{Rig: "beads", Branch: "polecat/max", QueuedAt: base},
+	}
+
+	queues := Build(events, listed)
+	if len(queues) != 2 {
+		t.Fatalf("expected 2 queues, got %d", len(queues))
+	}
+	if queues[0].Rig != "beads" || queues[1].Rig != "gastown" {
+		t.Errorf("queues not sorted by rig: %s, %s", queues[0].Rig, queues[1].Rig)
+	}
+
+	states := make(map[string]model.MergeState)
+	for _, mr := range queues[1].Requests {
+		states[mr.Branch] = mr.State
+	}
+	if states["polecat/nux"] != model.MergeQueued {
+		t.Errorf("re-queued failure state = %s, want queued", states["polecat/nux"])
+	}
+	if states["polecat/furiosa"] != model.MergeInFlight {
+		t.Errorf("processing state = %s, want in_flight", states["polecat/furiosa"])
+	}
+}
+
+func TestBuildMatchesBranchWithoutID(t *testing.T) {
+	base := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	events := []model.Event{
+		event(t, base, "done", "gastown/Nux", map[string]string{"rig": "gastown", "branch": "polecat/nux", "bead": "gt-002"}),
+		event(t, base.Add(time.Minute), "merge_failed", "gastown/refinery", map[string]string{"branch": "polecat/nux", "mr": "mr-7", "reason": "conflict"}),
+		event(t, base.Add(2*time.Minute), "merge_started", "gastown/refinery", map[string]string{"branch": "polecat/nux"}),
+	}
+	listed := []model.MergeRequest{
+		{ID: "mr-7", Rig: "gastown", Branch: "polecat/nux", State: "processing"},
+	}
+
+	queues := Build(events, listed)
+	if len(queues) != 1 || len(queues[0].Requests) != 1 {
+		t.Fatalf("expected a single request, got %+v", queues)
+	}
+	mr := queues[0].Requests[0]
+	if mr.ID != "mr-7" || mr.Bead != "gt-002" {
+		t.Errorf("request = %s/%s, want mr-7/gt-002", mr.ID, mr.Bead)
+	}
+	if mr.State != model.MergeInFlight || mr.Failures != 1 {
+		t.Errorf("state = %s failures = %d, want in_flight with 1 failure", mr.State, mr.Failures)
+	}
+	if !mr.QueuedAt.Equal(base) {
+		t.Errorf("QueuedAt = %v, want %v", mr.QueuedAt, base)
+	}
+}
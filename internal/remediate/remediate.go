@@ -0,0 +1,287 @@
+// Package remediate runs opt-in remediation policies on stuck polecats:
+// when a polecat becomes stuck, a policy's steps (nudge, restart the
+// session, nuke and re-sling its bead) run in order, each once its delay
+// since the polecat became stuck has passed. Every step is written to the
+// audit log.
+package remediate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/audit"
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Step actions.
+const (
+	ActionNudge       = "nudge"
+	ActionRestart     = "restart"
+	ActionNukeResling = "nuke_resling"
+)
+
+// defaultNudge is sent when a nudge step has no message.
+const defaultNudge = "gastop: you look stuck ({reason}). Please continue your work or escalate."
+
+// Config configures remediation. It is read from the [remediation] table
+// of the config file. Nothing runs unless Enabled is set and a policy
+// covers the polecat's rig.
+type Config struct {
+	Enabled bool `toml:"enabled"`
+	// DryRun logs what each step would do without doing it.
+	DryRun   bool     `toml:"dry_run"`
+	Policies []Policy `toml:"policies"`
+}
+
+// Policy is a sequence of steps for stuck polecats in some rigs.
+type Policy struct {
+	Name string `toml:"name"`
+	// Rigs the policy covers; empty covers every rig not named by another
+	// policy.
+	Rigs  []string `toml:"rigs"`
+	Steps []Step   `toml:"steps"`
+}
+
+// Step is one remediation action, run After the polecat became stuck.
+type Step struct {
+	Action  string        `toml:"action"` // nudge, restart, nuke_resling
+	After   time.Duration `toml:"after"`
+	Message string        `toml:"message"` // For nudge; {reason} is the stuck reason
+}
+
+// Validate checks the policies, so a typo fails at startup rather than
+// when a polecat gets stuck.
+func (c Config) Validate() error {
+	for i, p := range c.Policies {
+		if p.Name == "" {
+			return fmt.Errorf("policy %d: missing name", i+1)
+		}
+		if len(p.Steps) == 0 {
+			return fmt.Errorf("policy %q: no steps", p.Name)
+		}
+		for j, s := range p.Steps {
+			switch s.Action {
+			case ActionNudge, ActionRestart, ActionNukeResling:
+			default:
+				return fmt.Errorf("policy %q: step %d: unknown action %q", p.Name, j+1, s.Action)
+			}
+			if s.After < 0 || (j > 0 && s.After < p.Steps[j-1].After) {
+				return fmt.Errorf("policy %q: step %d: delays must not decrease", p.Name, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// policyFor returns the policy covering a rig: one naming the rig, else
+// the first one naming no rigs.
+func (c Config) policyFor(rig string) *Policy {
+	var fallback *Policy
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if len(p.Rigs) == 0 {
+			if fallback == nil {
+				fallback = p
+			}
+			continue
+		}
+		for _, r := range p.Rigs {
+			if r == rig {
+				return p
+			}
+		}
+	}
+	return fallback
+}
+
+// Actions carries out remediation steps. The adapter implements it.
+type Actions interface {
+	NudgePolecat(ctx context.Context, rig, name, message string) error
+	RestartPolecat(ctx context.Context, rig, name string) error
+	NukePolecat(ctx context.Context, rig, name string) error
+	SlingBead(ctx context.Context, beadID, rig string) error
+}
+
+// episode is a stuck polecat working through its policy.
+type episode struct {
+	policy  *Policy
+	since   time.Time // When the polecat became stuck
+	next    int       // Index of the next step
+	bead    string    // Work on the hook when the episode started
+	running bool      // A step is in flight
+}
+
+// Remediator tracks stuck polecats across refreshes and runs their
+// policies' steps.
+type Remediator struct {
+	cfg     Config
+	actions Actions
+	audit   *audit.Log
+	now     func() time.Time
+
+	// OnStep is called with the audit entry of each step after it runs.
+	// Optional.
+	OnStep func(e audit.Entry)
+	// OnError is called when the audit log can't be written. Optional.
+	OnError func(err error)
+
+	mu       sync.Mutex
+	episodes map[string]*episode // rig/name -> episode
+	primed   bool                // Observed once
+	pending  sync.WaitGroup      // Steps in flight
+}
+
+// New creates a remediator. It returns nil when remediation is disabled.
+func New(cfg Config, actions Actions, log *audit.Log) *Remediator {
+	if !cfg.Enabled || len(cfg.Policies) == 0 {
+		return nil
+	}
+	return &Remediator{
+		cfg:      cfg,
+		actions:  actions,
+		audit:    log,
+		now:      time.Now,
+		episodes: make(map[string]*episode),
+	}
+}
+
+// Observe starts remediation for polecats that became stuck, runs steps
+// that are due, and ends it for polecats that are no longer stuck or
+// gone. Snoozed polecats are left alone. Steps run in the background.
+//
+// The first call only primes: polecats already stuck when gastop starts
+// are left alone until they clear and get stuck again, so a restart
+// doesn't act on everything at once.
+func (r *Remediator) Observe(ctx context.Context, polecats []model.Polecat) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	primed := r.primed
+	r.primed = true
+
+	seen := make(map[string]bool, len(polecats))
+	for i := range polecats {
+		p := polecats[i]
+		key := p.FullName()
+		seen[key] = true
+
+		ep := r.episodes[key]
+		if !p.Stuck || p.Snoozed {
+			delete(r.episodes, key)
+			continue
+		}
+		if ep == nil {
+			policy := r.cfg.policyFor(p.Rig)
+			if policy == nil {
+				continue
+			}
+			since := p.StuckSince
+			if since.IsZero() {
+				since = now
+			}
+			ep = &episode{policy: policy, since: since, bead: p.CurrentBead()}
+			if !primed {
+				ep.next = len(policy.Steps) // Stuck before we started
+			}
+			r.episodes[key] = ep
+		}
+		if ep.running || ep.next >= len(ep.policy.Steps) {
+			continue
+		}
+		step := ep.policy.Steps[ep.next]
+		if now.Sub(ep.since) < step.After {
+			continue
+		}
+
+		entry := audit.Entry{
+			Source: audit.SourceRemediation,
+			Action: step.Action,
+			Target: key,
+			Bead:   ep.bead,
+			Policy: ep.policy.Name,
+			Step:   fmt.Sprintf("%d/%d", ep.next+1, len(ep.policy.Steps)),
+			Reason: p.StuckReason,
+		}
+		ep.next++
+		ep.running = true
+		r.pending.Add(1)
+		go func() {
+			defer r.pending.Done()
+			r.run(context.WithoutCancel(ctx), step, &p, entry)
+			r.mu.Lock()
+			ep.running = false
+			r.mu.Unlock()
+		}()
+	}
+
+	// Polecats that are gone (nuked, finished) need nothing more
+	for key := range r.episodes {
+		if !seen[key] {
+			delete(r.episodes, key)
+		}
+	}
+}
+
+// run carries out one step, or only records it in dry-run mode.
+func (r *Remediator) run(ctx context.Context, step Step, p *model.Polecat, entry audit.Entry) {
+	var err error
+	switch {
+	case r.cfg.DryRun:
+		entry.Result = audit.ResultDryRun
+	case step.Action == ActionNudge:
+		err = r.actions.NudgePolecat(ctx, p.Rig, p.Name, nudgeMessage(step.Message, p.StuckReason))
+	case step.Action == ActionRestart:
+		err = r.actions.RestartPolecat(ctx, p.Rig, p.Name)
+	case step.Action == ActionNukeResling:
+		err = r.nukeResling(ctx, p, entry.Bead)
+	}
+	if entry.Result == "" {
+		entry.Result = audit.ResultOK
+		if err != nil {
+			entry.Result = audit.ResultError
+			entry.Error = err.Error()
+		}
+	}
+
+	entry.Time = r.now()
+	if err := r.audit.Record(entry); err != nil && r.OnError != nil {
+		r.OnError(fmt.Errorf("audit log: %w", err))
+	}
+	if r.OnStep != nil {
+		r.OnStep(entry)
+	}
+}
+
+// nukeResling nukes a polecat and slings its bead back to the rig, so a
+// fresh polecat picks it up.
+func (r *Remediator) nukeResling(ctx context.Context, p *model.Polecat, bead string) error {
+	if err := r.actions.NukePolecat(ctx, p.Rig, p.Name); err != nil {
+		return fmt.Errorf("nuke: %w", err)
+	}
+	if bead == "" {
+		return nil // Nothing to re-sling
+	}
+	if err := r.actions.SlingBead(ctx, bead, p.Rig); err != nil {
+		return fmt.Errorf("sling %s: %w", bead, err)
+	}
+	return nil
+}
+
+// Wait waits for steps in flight.
+func (r *Remediator) Wait() {
+	r.pending.Wait()
+}
+
+// nudgeMessage fills in a nudge message template.
+func nudgeMessage(msg, reason string) string {
+	if msg == "" {
+		msg = defaultNudge
+	}
+	if reason == "" {
+		reason = "no progress"
+	}
+	return strings.ReplaceAll(msg, "{reason}", reason)
+}
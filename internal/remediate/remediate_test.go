@@ -0,0 +1,281 @@
+package remediate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/audit"
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// fakeActions records the actions taken.
+type fakeActions struct {
+	mu       sync.Mutex
+	calls    []string
+	nukeFail bool
+}
+
+func (f *fakeActions) record(call string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+}
+
+func (f *fakeActions) NudgePolecat(ctx context.Context, rig, name, message string) error {
+	f.record("nudge " + rig + "/" + name + ": " + message)
+	return nil
+}
+
+func (f *fakeActions) RestartPolecat(ctx context.Context, rig, name string) error {
+	f.record("restart " + rig + "/" + name)
+	return nil
+}
+
+func (f *fakeActions) NukePolecat(ctx context.Context, rig, name string) error {
+	f.record("nuke " + rig + "/" + name)
+	if f.nukeFail {
+		return errors.New("session busy")
+	}
+	return nil
+}
+
+func (f *fakeActions) SlingBead(ctx context.Context, beadID, rig string) error {
+	f.record("sling " + beadID + " " + rig)
+	return nil
+}
+
+// readAudit reads the audit log entries.
+func readAudit(t *testing.T, path string) []audit.Entry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []audit.Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("bad audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+var testPolicies = []Policy{
+	{
+		Name: "default",
+		Steps: []Step{
+			{Action: ActionNudge, Message: "stuck: {reason}"},
+			{Action: ActionRestart, After: 10 * time.Minute},
+			{Action: ActionNukeResling, After: 30 * time.Minute},
+		},
+	},
+	{
+		Name:  "gentle",
+		Rigs:  []string{"beads"},
+		Steps: []Step{{Action: ActionNudge}},
+	},
+}
+
+func TestRemediatorSteps(t *testing.T) {
+	actions := &fakeActions{}
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	r := New(Config{Enabled: true, Policies: testPolicies}, actions, audit.NewLog(logPath))
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	polecats := []model.Polecat{
+		{Name: "Toast", Rig: "gastown", HookedBead: "gt-1", Stuck: true, StuckReason: "no activity"},
+		{Name: "Nux", Rig: "gastown"},
+	}
+	step := func(d time.Duration) {
+		now = now.Add(d)
+		r.Observe(ctx, polecats)
+		r.Wait()
+	}
+
+	// Toast gets stuck after the priming pass
+	r.Observe(ctx, nil)
+	step(0)
+	step(5 * time.Minute) // Restart not due yet
+	step(5 * time.Minute)
+	step(20 * time.Minute)
+	step(time.Hour) // Policy exhausted
+
+	want := []string{
+		"nudge gastown/Toast: stuck: no activity",
+		"restart gastown/Toast",
+		"nuke gastown/Toast",
+		"sling gt-1 gastown",
+	}
+	if strings.Join(actions.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", actions.calls, want)
+	}
+
+	entries := readAudit(t, logPath)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 audit entries, got %+v", entries)
+	}
+	if e := entries[2]; e.Action != ActionNukeResling || e.Step != "3/3" || e.Bead != "gt-1" ||
+		e.Policy != "default" || e.Result != audit.ResultOK || e.Source != audit.SourceRemediation {
+		t.Errorf("unexpected audit entry: %+v", e)
+	}
+
+	// Clearing ends the episode; becoming stuck again starts over
+	polecats[0].Stuck = false
+	step(time.Minute)
+	polecats[0].Stuck = true
+	step(time.Minute)
+	if last := actions.calls[len(actions.calls)-1]; !strings.HasPrefix(last, "nudge gastown/Toast") {
+		t.Errorf("expected a new nudge, got %q", last)
+	}
+
+	// Snoozed polecats are left alone
+	actions.calls = nil
+	polecats[1].Stuck, polecats[1].Snoozed = true, true
+	step(time.Hour)
+	for _, c := range actions.calls {
+		if strings.Contains(c, "Nux") {
+			t.Errorf("expected snoozed Nux to be left alone, got %q", c)
+		}
+	}
+}
+
+func TestRemediatorPolicies(t *testing.T) {
+	actions := &fakeActions{nukeFail: true}
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := Config{Enabled: true, Policies: []Policy{
+		{Name: "gentle", Rigs: []string{"beads"}, Steps: []Step{{Action: ActionNudge}}},
+		{Name: "harsh", Steps: []Step{{Action: ActionNukeResling}}},
+	}}
+	r := New(cfg, actions, audit.NewLog(logPath))
+	var mu sync.Mutex
+	var steps []audit.Entry
+	r.OnStep = func(e audit.Entry) {
+		mu.Lock()
+		steps = append(steps, e)
+		mu.Unlock()
+	}
+
+	r.Observe(context.Background(), nil)
+	r.Observe(context.Background(), []model.Polecat{
+		{Name: "Toast", Rig: "beads", Stuck: true},
+		{Name: "Nux", Rig: "gastown", AssignedBead: "gt-2", Stuck: true},
+	})
+	r.Wait()
+
+	entries := readAudit(t, logPath)
+	byTarget := make(map[string]audit.Entry)
+	for _, e := range entries {
+		byTarget[e.Target] = e
+	}
+	if e := byTarget["beads/Toast"]; e.Policy != "gentle" || e.Action != ActionNudge {
+		t.Errorf("expected the rig's own policy, got %+v", e)
+	}
+	if e := byTarget["gastown/Nux"]; e.Policy != "harsh" || e.Result != audit.ResultError || !strings.Contains(e.Error, "session busy") {
+		t.Errorf("expected the fallback policy to fail on nuke, got %+v", e)
+	}
+	for _, c := range actions.calls {
+		if strings.HasPrefix(c, "sling") {
+			t.Errorf("expected no re-sling after a failed nuke, got %q", c)
+		}
+	}
+	if len(steps) != 2 {
+		t.Errorf("expected OnStep for both steps, got %d", len(steps))
+	}
+}
+
+func TestRemediatorDryRun(t *testing.T) {
+	actions := &fakeActions{}
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	r := New(Config{Enabled: true, DryRun: true, Policies: testPolicies}, actions, audit.NewLog(logPath))
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	polecats := []model.Polecat{{Name: "Toast", Rig: "gastown", Stuck: true}}
+	r.Observe(context.Background(), nil)
+	for i := 0; i < 4; i++ {
+		r.Observe(context.Background(), polecats)
+		r.Wait()
+		now = now.Add(time.Hour)
+	}
+
+	if len(actions.calls) != 0 {
+		t.Errorf("expected no actions in dry-run, got %q", actions.calls)
+	}
+	entries := readAudit(t, logPath)
+	if len(entries) != 3 {
+		t.Fatalf("expected every step to be logged, got %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Result != audit.ResultDryRun {
+			t.Errorf("expected dry-run result, got %+v", e)
+		}
+	}
+}
+
+func TestRemediatorPriming(t *testing.T) {
+	actions := &fakeActions{}
+	r := New(Config{Enabled: true, Policies: testPolicies}, actions, audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl")))
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Toast was stuck before gastop started
+	polecats := []model.Polecat{{Name: "Toast", Rig: "gastown", Stuck: true, StuckSince: now.Add(-time.Hour)}}
+	for i := 0; i < 3; i++ {
+		r.Observe(ctx, polecats)
+		r.Wait()
+		now = now.Add(time.Hour)
+	}
+	if len(actions.calls) != 0 {
+		t.Fatalf("expected polecats stuck at startup to be left alone, got %q", actions.calls)
+	}
+
+	// Once it clears, a new stuck episode is remediated, with delays from
+	// when it got stuck rather than when it was first seen
+	polecats[0].Stuck = false
+	r.Observe(ctx, polecats)
+	polecats[0].Stuck, polecats[0].StuckSince = true, now.Add(-20*time.Minute)
+	for i := 0; i < 2; i++ {
+		r.Observe(ctx, polecats)
+		r.Wait()
+	}
+	want := []string{"nudge gastown/Toast: stuck: no progress", "restart gastown/Toast"}
+	if strings.Join(actions.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", actions.calls, want)
+	}
+}
+
+func TestRemediationConfig(t *testing.T) {
+	if New(Config{Policies: testPolicies}, nil, nil) != nil {
+		t.Error("expected no remediator unless enabled")
+	}
+	if err := (Config{Policies: testPolicies}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+
+	bad := []Policy{
+		{Name: "", Steps: []Step{{Action: ActionNudge}}},
+		{Name: "empty"},
+		{Name: "typo", Steps: []Step{{Action: "nuke"}}},
+		{Name: "order", Steps: []Step{{Action: ActionRestart, After: time.Hour}, {Action: ActionNudge}}},
+	}
+	for _, p := range bad {
+		if err := (Config{Policies: []Policy{p}}).Validate(); err == nil {
+			t.Errorf("expected policy %+v to be rejected", p)
+		}
+	}
+}
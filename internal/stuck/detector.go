@@ -1,30 +1,100 @@
 package stuck
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/davidsenack/gastop/internal/model"
 )
 
-// Detector checks for stuck work.
+// Detector checks for stuck work by evaluating rules against beads,
+// polecats and convoys, and by looking for anomalies in the event log.
 type Detector struct {
-	// StuckThreshold is how long a bead can be in_progress without updates
-	// before being considered stuck.
-	StuckThreshold time.Duration
+	cfg   Config
+	rules []Rule
 
-	// HeartbeatThreshold is how long since a polecat's last activity
-	// before being considered stuck.
-	HeartbeatThreshold time.Duration
+	mu        sync.RWMutex
+	anomalies []Anomaly
+
+	// RigOf maps a bead ID to its rig, for per-rig thresholds. Optional.
+	RigOf func(id string) string
 }
 
-// NewDetector creates a detector with default thresholds.
+// NewDetector creates a detector with the built-in rules and a single
+// threshold.
 func NewDetector(stuckMinutes int) *Detector {
 	if stuckMinutes <= 0 {
 		stuckMinutes = 30
 	}
-	return &Detector{
-		StuckThreshold:     time.Duration(stuckMinutes) * time.Minute,
-		HeartbeatThreshold: time.Duration(stuckMinutes) * time.Minute,
+	d, _ := NewDetectorFromConfig(Config{Threshold: time.Duration(stuckMinutes) * time.Minute})
+	return d
+}
+
+// NewDetectorFromConfig creates a detector from configured thresholds and
+// rules.
+func NewDetectorFromConfig(cfg Config) (*Detector, error) {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 30 * time.Minute
+	}
+	rules, err := compileRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Detector{cfg: cfg, rules: rules}, nil
+}
+
+// Threshold returns the stuck threshold for a rig and priority (-1 for
+// entities without one). Priority overrides take precedence over rig ones.
+func (d *Detector) Threshold(rig string, priority int) time.Duration {
+	if priority >= 0 {
+		if t, ok := d.cfg.PriorityThresholds[fmt.Sprintf("P%d", priority)]; ok {
+			return t
+		}
+	}
+	if t, ok := d.cfg.RigThresholds[rig]; ok {
+		return t
+	}
+	return d.cfg.Threshold
+}
+
+// evaluate returns the most severe matching rule (the first, on ties).
+func (d *Detector) evaluate(entity string, f facts, threshold time.Duration) (*Rule, bool) {
+	var match *Rule
+	for i := range d.rules {
+		r := &d.rules[i]
+		if r.Entity != entity {
+			continue
+		}
+		if match != nil && r.Severity.Rank() <= match.Severity.Rank() {
+			continue
+		}
+		holds := true
+		for j := range r.conds {
+			if !r.conds[j].eval(f, threshold) {
+				holds = false
+				break
+			}
+		}
+		if holds {
+			match = r
+		}
+	}
+	return match, match != nil
+}
+
+// since returns the duration since t, or false for a zero time.
+func since(t time.Time) (time.Duration, bool) {
+	if t.IsZero() {
+		return 0, false
+	}
+	return time.Since(t), true
+}
+
+// setSince records the duration since t, if t is set.
+func (f facts) setSince(name string, t time.Time) {
+	if d, ok := since(t); ok {
+		f[name] = d
 	}
 }
 
@@ -35,29 +105,56 @@ func (d *Detector) CheckBeads(beads []model.Bead) {
 	}
 }
 
+// beadFacts returns the rule fields for a bead.
+func (d *Detector) beadFacts(b *model.Bead) facts {
+	f := facts{
+		"id":       b.ID,
+		"status":   string(b.Status.Canonical()),
+		"type":     string(b.IssueType.Canonical()),
+		"priority": float64(b.Priority),
+		"assignee": b.Assignee,
+		"blockers": float64(b.DependencyCount()),
+	}
+	if d.RigOf != nil {
+		f["rig"] = d.RigOf(b.ID)
+	}
+	// A bead that was never updated counts as stale since forever
+	f["since_update"] = time.Since(b.UpdatedAt)
+	f.setSince("since_created", b.CreatedAt)
+
+	if m := b.Molecule; m != nil {
+		if i := m.CurrentIndex(); i >= 0 && m.Steps[i].Status.IsActive() {
+			f["step"] = m.Steps[i].ID
+			f["step_elapsed"] = m.Timeline(time.Now())[i].Duration
+			if typical := m.TypicalStepDuration(); typical > 0 {
+				f["step_typical"] = typical
+			}
+		}
+	}
+	return f
+}
+
 // checkBead checks a single bead for stuck conditions.
 func (d *Detector) checkBead(b *model.Bead) {
 	// Already closed or deferred - not stuck
-	if b.Status == "closed" || b.Status == "deferred" {
+	if b.Status.IsDone() || b.Status.Is(model.StatusDeferred) {
 		return
 	}
 
-	// In progress but no updates for too long
-	if b.Status == "in_progress" {
-		if time.Since(b.UpdatedAt) > d.StuckThreshold {
-			b.Stuck = true
-			b.StuckReason = "No updates for " + humanizeDuration(time.Since(b.UpdatedAt))
-			return
-		}
-	}
-
-	// Blocked for a long time
-	if b.Status == "blocked" {
-		if time.Since(b.UpdatedAt) > d.StuckThreshold*2 {
-			b.Stuck = true
-			b.StuckReason = "Blocked for " + humanizeDuration(time.Since(b.UpdatedAt))
-			return
-		}
+	f := d.beadFacts(b)
+	rig, _ := f["rig"].(string)
+	threshold := d.Threshold(rig, b.Priority)
+	r, matched := d.evaluate(EntityBead, f, threshold)
+	a, anomalous := d.beadAnomaly(b)
+	switch {
+	case anomalous && (!matched || a.Severity.Rank() > r.Severity.Rank()):
+		b.Stuck = true
+		b.StuckReason = a.Reason
+		b.StuckLevel = a.Severity
+	case matched:
+		b.Stuck = true
+		b.StuckReason = r.reason(f, threshold)
+		b.StuckLevel = r.Severity
 	}
 }
 
@@ -70,106 +167,159 @@ func (d *Detector) CheckPolecats(polecats []model.Polecat) {
 
 // checkPolecat checks a single polecat for stuck conditions.
 func (d *Detector) checkPolecat(p *model.Polecat) {
-	// Already marked stuck by Gas Town
-	if p.State == "stuck" {
-		p.Stuck = true
-		if p.StuckReason == "" {
-			p.StuckReason = "Marked stuck by Gas Town"
-		}
-		return
+	f := facts{
+		"name":          p.Name,
+		"rig":           p.Rig,
+		"state":         string(p.State.Canonical()),
+		"assigned_bead": p.AssignedBead,
+		"hooked_bead":   p.HookedBead,
+		"running":       p.Running,
+		"attached":      p.Attached,
 	}
+	f.setSince("since_activity", p.LastActivity)
+	f.setSince("since_created", p.CreatedAt)
 
-	// Working but no assigned bead
-	if p.State == "working" && p.AssignedBead == "" {
+	threshold := d.Threshold(p.Rig, -1)
+	r, matched := d.evaluate(EntityPolecat, f, threshold)
+	a, anomalous := d.polecatAnomaly(p)
+	switch {
+	case anomalous && (!matched || a.Severity.Rank() > r.Severity.Rank()):
 		p.Stuck = true
-		p.StuckReason = "Working but no assigned bead"
-		return
-	}
-
-	// Working but no activity for too long
-	if p.State == "working" && !p.LastActivity.IsZero() {
-		if time.Since(p.LastActivity) > d.HeartbeatThreshold {
-			p.Stuck = true
-			p.StuckReason = "No activity for " + humanizeDuration(time.Since(p.LastActivity))
-			return
-		}
-	}
-
-	// Session not running but state says working
-	if p.State == "working" && !p.Running {
+		p.StuckReason = a.Reason
+		p.StuckLevel = a.Severity
+	case matched:
 		p.Stuck = true
-		p.StuckReason = "Session not running"
-		return
+		p.StuckReason = r.reason(f, threshold)
+		p.StuckLevel = r.Severity
 	}
 }
 
-// CheckConvoys marks convoys as stuck if they have stuck beads.
-func (d *Detector) CheckConvoys(convoys []model.Convoy, getBeads func(ids []string) []model.Bead) {
+// CheckConvoys marks convoys as stuck from their own state and from the
+// stuck state of their tracked beads and swarm polecats. beads and
+// polecats should already be checked, and should include tracked beads
+// outside the listed page so they aren't missed.
+func (d *Detector) CheckConvoys(convoys []model.Convoy, beads []model.Bead, polecats []model.Polecat) {
+	byID := make(map[string]*model.Bead, len(beads))
+	for i := range beads {
+		byID[beads[i].ID] = &beads[i]
+	}
 	for i := range convoys {
-		d.checkConvoy(&convoys[i], getBeads)
+		d.checkConvoy(&convoys[i], byID, polecats)
 	}
 }
 
 // checkConvoy checks a convoy for stuck conditions.
-func (d *Detector) checkConvoy(c *model.Convoy, getBeads func(ids []string) []model.Bead) {
-	if c.Status == "closed" {
-		return
+func (d *Detector) checkConvoy(c *model.Convoy, beads map[string]*model.Bead, polecats []model.Polecat) {
+	f := facts{
+		"id":      c.ID,
+		"status":  string(c.Status.Canonical()),
+		"tracked": float64(len(c.TrackedIDs)),
 	}
 
-	// Check if any tracked beads are stuck
-	if getBeads != nil && len(c.TrackedIDs) > 0 {
-		beads := getBeads(c.TrackedIDs)
-		for _, b := range beads {
-			if b.Stuck {
-				c.Stuck = true
-				c.StuckReason = "Has stuck beads"
-				return
-			}
+	// Tracked beads that are stuck, most severe first
+	c.StuckBeads = nil
+	var worst *model.Bead
+	for _, id := range c.TrackedIDs {
+		// Snoozed beads don't flag their convoy either
+		b, ok := beads[id]
+		if !ok || !b.Stuck || b.Snoozed {
+			continue
+		}
+		c.StuckBeads = append(c.StuckBeads, id)
+		if worst == nil || b.StuckLevel.Rank() > worst.StuckLevel.Rank() {
+			worst = b
 		}
 	}
+	if len(c.TrackedIDs) > 0 {
+		f["stuck_beads"] = float64(len(c.StuckBeads))
+		f["stuck_more"] = ""
+	}
+	if worst != nil {
+		f["stuck_bead"] = worst.ID
+		f["stuck_bead_reason"] = worst.StuckReason
+		if n := len(c.StuckBeads) - 1; n > 0 {
+			f["stuck_more"] = fmt.Sprintf(", +%d more", n)
+		}
+		c.StuckBeads = moveFirst(c.StuckBeads, worst.ID)
+	}
 
-	// Determine the reference time - use UpdatedAt if set, otherwise CreatedAt
+	stuckPolecats := 0
+	for _, p := range c.Swarm(polecats) {
+		if !p.Stuck || p.Snoozed {
+			continue
+		}
+		if stuckPolecats == 0 {
+			f["stuck_polecat"] = p.FullName()
+			f["stuck_polecat_reason"] = p.StuckReason
+		}
+		stuckPolecats++
+	}
+	f["stuck_polecats"] = float64(stuckPolecats)
+
+	// Use UpdatedAt if set, otherwise CreatedAt
 	refTime := c.UpdatedAt
 	if refTime.IsZero() {
 		refTime = c.CreatedAt
 	}
+	f.setSince("since_update", refTime)
 
-	// Skip stuck check if we still don't have a valid timestamp
-	if refTime.IsZero() {
-		return
+	threshold := d.Threshold("", -1)
+	if r, ok := d.evaluate(EntityConvoy, f, threshold); ok {
+		c.Stuck = true
+		c.StuckReason = r.reason(f, threshold)
+		c.StuckLevel = r.Severity
 	}
+}
 
-	// No progress for a long time
-	if time.Since(refTime) > d.StuckThreshold*2 {
-		c.Stuck = true
-		c.StuckReason = "No progress for " + humanizeDuration(time.Since(refTime))
+// moveFirst moves id to the front of ids.
+func moveFirst(ids []string, id string) []string {
+	out := []string{id}
+	for _, other := range ids {
+		if other != id {
+			out = append(out, other)
+		}
 	}
+	return out
 }
 
-// StuckSummary returns counts of stuck items.
+// StuckSummary returns counts of stuck items. Snoozed items are counted
+// separately, not as stuck.
 type StuckSummary struct {
 	StuckBeads    int
 	StuckPolecats int
 	StuckConvoys  int
+	Critical      int
+	Snoozed       int
+}
+
+// Total returns the number of stuck, unsnoozed items.
+func (s StuckSummary) Total() int {
+	return s.StuckBeads + s.StuckPolecats + s.StuckConvoys
 }
 
 // Summarize returns a summary of stuck items.
 func (d *Detector) Summarize(beads []model.Bead, polecats []model.Polecat, convoys []model.Convoy) StuckSummary {
 	var s StuckSummary
-	for _, b := range beads {
-		if b.Stuck {
-			s.StuckBeads++
+	count := func(stuck, snoozed bool, level model.Severity, n *int) {
+		switch {
+		case !stuck:
+		case snoozed:
+			s.Snoozed++
+		default:
+			*n++
+			if level == model.SeverityCritical {
+				s.Critical++
+			}
 		}
 	}
+	for _, b := range beads {
+		count(b.Stuck, b.Snoozed, b.StuckLevel, &s.StuckBeads)
+	}
 	for _, p := range polecats {
-		if p.Stuck {
-			s.StuckPolecats++
-		}
+		count(p.Stuck, p.Snoozed, p.StuckLevel, &s.StuckPolecats)
 	}
 	for _, c := range convoys {
-		if c.Stuck {
-			s.StuckConvoys++
-		}
+		count(c.Stuck, c.Snoozed, c.StuckLevel, &s.StuckConvoys)
 	}
 	return s
 }
@@ -188,5 +338,5 @@ func humanizeDuration(d time.Duration) string {
 	if days == 1 {
 		return "1 day"
 	}
-	return string(rune(days+'0')) + " days"
+	return fmt.Sprintf("%d days", days)
 }
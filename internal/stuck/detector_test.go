@@ -1,9 +1,12 @@
 package stuck
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/davidsenack/gastop/internal/model"
 )
 
@@ -26,6 +29,15 @@ func TestDetectorCheckBeads(t *testing.T) {
 			Status:    "closed",
 			UpdatedAt: time.Now().Add(-2 * time.Hour), // Not stuck - closed
 		},
+		{
+			ID:     "test-4",
+			Status: "in_progress", // Stuck - never updated
+		},
+		{
+			ID:        "test-5",
+			Status:    "hooked",
+			UpdatedAt: time.Now().Add(-45 * time.Minute), // Not stuck - only in_progress goes stale
+		},
 	}
 
 	d.CheckBeads(beads)
@@ -44,6 +56,49 @@ func TestDetectorCheckBeads(t *testing.T) {
 	if beads[2].Stuck {
 		t.Error("expected bead test-3 to not be stuck")
 	}
+
+	if !beads[3].Stuck {
+		t.Error("expected bead test-4 without an update time to be stuck")
+	}
+
+	if beads[4].Stuck {
+		t.Error("expected hooked bead test-5 to not be stuck")
+	}
+}
+
+func TestDetectorCheckMolecules(t *testing.T) {
+	d := NewDetector(600) // High threshold so only the step check fires
+
+	now := time.Now()
+	closedAt := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+	steps := func(currentStart time.Duration) []model.Bead {
+		return []model.Bead{
+			{ID: "mol-1.1", Status: "closed", CreatedAt: now.Add(-5 * time.Hour), ClosedAt: closedAt(4 * time.Hour)},
+			{ID: "mol-1.2", Status: "closed", CreatedAt: now.Add(-5 * time.Hour), ClosedAt: closedAt(currentStart)},
+			{ID: "mol-1.3", Status: "in_progress", CreatedAt: now.Add(-5 * time.Hour), UpdatedAt: now},
+		}
+	}
+
+	beads := []model.Bead{
+		// Steps took 1h each; current step has run 3h
+		{ID: "mol-1", Status: "in_progress", IssueType: "molecule", UpdatedAt: now,
+			Molecule: &model.Molecule{Steps: steps(3 * time.Hour)}},
+		// Current step has run 30m
+		{ID: "mol-2", Status: "in_progress", IssueType: "molecule", UpdatedAt: now,
+			Molecule: &model.Molecule{Steps: steps(30 * time.Minute)}},
+	}
+
+	d.CheckBeads(beads)
+
+	if !beads[0].Stuck {
+		t.Error("expected mol-1 to be stuck on an overdue step")
+	}
+	if beads[1].Stuck {
+		t.Errorf("expected mol-2 to not be stuck, got %q", beads[1].StuckReason)
+	}
 }
 
 func TestDetectorCheckPolecats(t *testing.T) {
@@ -103,11 +158,13 @@ func TestDetectorSummarize(t *testing.T) {
 
 	beads := []model.Bead{
 		{Stuck: true},
-		{Stuck: true},
+		{Stuck: true, StuckLevel: model.SeverityCritical},
 		{Stuck: false},
+		{Stuck: true, Snoozed: true},
 	}
 	polecats := []model.Polecat{
 		{Stuck: true},
+		{Stuck: true, Snoozed: true},
 	}
 	convoys := []model.Convoy{
 		{Stuck: false},
@@ -125,4 +182,534 @@ func TestDetectorSummarize(t *testing.T) {
 	if summary.StuckConvoys != 1 {
 		t.Errorf("expected 1 stuck convoy, got %d", summary.StuckConvoys)
 	}
+	if summary.Critical != 1 || summary.Snoozed != 2 || summary.Total() != 4 {
+		t.Errorf("expected 1 critical, 2 snoozed, 4 total, got %+v", summary)
+	}
+}
+
+func TestRuleConditions(t *testing.T) {
+	f := facts{
+		"status":       "in_progress",
+		"priority":     float64(1),
+		"running":      false,
+		"assignee":     "",
+		"since_update": 45 * time.Minute,
+		"step_elapsed": 3 * time.Hour,
+		"step_typical": time.Hour,
+	}
+	threshold := 30 * time.Minute
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"status == in_progress", true},
+		{"status != in_progress", false},
+		{"status in open,in_progress", true},
+		{"status in open, blocked", false},
+		{"priority <= 1", true},
+		{"priority > 1", false},
+		{"running == false", true},
+		{"assignee == ''", true},
+		{"since_update > threshold", true},
+		{"since_update > 2*threshold", false},
+		{"since_update >= 45m", true},
+		{"since_update < 1h", true},
+		{"step_elapsed > 2*step_typical", true},
+		{"step_elapsed > 4*step_typical", false},
+		{"since_created > threshold", false}, // Absent fields never match
+	}
+
+	// Beads have no boolean fields, so borrow one from polecats.
+	fields := map[string]kind{"running": kindBool}
+	for name, kind := range fieldKinds[EntityBead] {
+		fields[name] = kind
+	}
+
+	for _, tt := range tests {
+		c, err := parseCondition(tt.cond, fields)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.cond, err)
+			continue
+		}
+		if got := c.eval(f, threshold); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestRuleCompileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"unknown entity", Rule{Name: "r", Entity: "rig", When: []string{"id == x"}}},
+		{"no conditions", Rule{Name: "r", Entity: EntityBead}},
+		{"unknown field", Rule{Name: "r", Entity: EntityBead, When: []string{"colour == red"}}},
+		{"kind mismatch", Rule{Name: "r", Entity: EntityBead, When: []string{"since_update > 3"}}},
+		{"ordering on string", Rule{Name: "r", Entity: EntityBead, When: []string{"status > open"}}},
+		{"scaled literal", Rule{Name: "r", Entity: EntityBead, When: []string{"since_update > 2*5m"}}},
+		{"bad severity", Rule{Name: "r", Entity: EntityBead, When: []string{"id == x"}, Severity: "fatal"}},
+		{"garbage", Rule{Name: "r", Entity: EntityBead, When: []string{"stuck!"}}},
+	}
+
+	for _, tt := range tests {
+		if err := (Config{Rules: []Rule{tt.rule}}).Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestRuleOverridesBuiltin(t *testing.T) {
+	rules, err := compileRules(Config{Rules: []Rule{
+		{Name: "bead-no-updates", Severity: model.SeverityCritical},
+		{Name: "bead-blocked", Disabled: true},
+	}})
+	if err != nil {
+		t.Fatalf("tuning a built-in: %v", err)
+	}
+	var found bool
+	for _, r := range rules {
+		switch r.Name {
+		case "bead-no-updates":
+			found = true
+			var builtin Rule
+			for _, b := range BuiltinRules() {
+				if b.Name == r.Name {
+					builtin = b
+				}
+			}
+			if r.Severity != model.SeverityCritical || r.Entity != builtin.Entity || strings.Join(r.When, ",") != strings.Join(builtin.When, ",") || r.Reason != builtin.Reason {
+				t.Errorf("merged rule = %+v, want the built-in with critical severity", r)
+			}
+		case "bead-blocked":
+			t.Error("disabled built-in still compiled")
+		}
+	}
+	if !found {
+		t.Error("bead-no-updates missing")
+	}
+}
+
+func TestDetectorConfig(t *testing.T) {
+	var cfg Config
+	_, err := toml.Decode(`
+threshold = "30m"
+
+[rig_thresholds]
+slowrig = "2h"
+
+[priority_thresholds]
+P0 = "10m"
+
+[[rules]]
+name = "bead-blocked"
+disabled = true
+
+[[rules]]
+name = "p0-unassigned"
+entity = "bead"
+when = ["priority == 0", "assignee == ''", "since_created > threshold"]
+severity = "critical"
+reason = "P0 unassigned for {since_created}"
+`, &cfg)
+	if err != nil {
+		t.Fatalf("decoding config: %v", err)
+	}
+	d, err := NewDetectorFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("compiling rules: %v", err)
+	}
+	d.RigOf = func(id string) string {
+		if id == "sr-1" {
+			return "slowrig"
+		}
+		return "gastown"
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		bead      model.Bead
+		wantStuck bool
+		wantLevel model.Severity
+		wantIn    string
+	}{
+		{"default threshold", model.Bead{ID: "gt-1", Status: "in_progress", Priority: 2, UpdatedAt: now.Add(-45 * time.Minute)},
+			true, model.SeverityWarning, "No updates"},
+		{"rig threshold", model.Bead{ID: "sr-1", Status: "in_progress", Priority: 2, UpdatedAt: now.Add(-45 * time.Minute)},
+			false, "", ""},
+		{"priority threshold beats rig", model.Bead{ID: "sr-1", Status: "in_progress", Priority: 0, Assignee: "x", UpdatedAt: now.Add(-15 * time.Minute)},
+			true, model.SeverityWarning, "No updates"},
+		{"disabled builtin", model.Bead{ID: "gt-2", Status: "blocked", Priority: 2, UpdatedAt: now.Add(-3 * time.Hour)},
+			false, "", ""},
+		{"custom rule wins on severity", model.Bead{ID: "gt-3", Status: "in_progress", Priority: 0, CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-time.Hour)},
+			true, model.SeverityCritical, "P0 unassigned for"},
+	}
+
+	for _, tt := range tests {
+		beads := []model.Bead{tt.bead}
+		d.CheckBeads(beads)
+		got := beads[0]
+		if got.Stuck != tt.wantStuck || got.StuckLevel != tt.wantLevel {
+			t.Errorf("%s: stuck=%v level=%q, want %v %q (%s)", tt.name, got.Stuck, got.StuckLevel, tt.wantStuck, tt.wantLevel, got.StuckReason)
+		}
+		if !strings.Contains(got.StuckReason, tt.wantIn) {
+			t.Errorf("%s: reason %q, want it to contain %q", tt.name, got.StuckReason, tt.wantIn)
+		}
+	}
+}
+
+func TestEventAnomalies(t *testing.T) {
+	now := time.Date(2026, 1, 22, 12, 0, 0, 0, time.UTC)
+	ev := func(ago time.Duration, typ, actor, payload string) model.Event {
+		e := model.Event{Timestamp: now.Add(-ago), Source: "gt", Type: typ, Actor: actor, Payload: []byte(payload)}
+		e.ParsePayload()
+		return e
+	}
+
+	tests := []struct {
+		name   string
+		events []model.Event
+		want   []string // kind:agent:bead per anomaly
+	}{
+		{"crash loop", []model.Event{
+			ev(9*time.Minute, "crash", "witness", `{"rig":"gastown","polecat":"Toast","exit_code":1}`),
+			ev(3*time.Minute, "crash", "gastown/polecats/Toast", `{"exit_code":1}`),
+		}, []string{"crash-loop:gastown/Toast:"}},
+		{"crashes too far apart", []model.Event{
+			ev(25*time.Minute, "crash", "gastown/Toast", `{}`),
+			ev(5*time.Minute, "crash", "gastown/Toast", `{}`),
+		}, nil},
+		{"old crash loop", []model.Event{
+			ev(3*time.Hour, "crash", "gastown/Toast", `{}`),
+			ev(3*time.Hour-time.Minute, "crash", "gastown/Toast", `{}`),
+		}, nil},
+		{"sling never started", []model.Event{
+			ev(50*time.Minute, "sling", "mayor", `{"bead":"gt-002","target":"gastown/polecats/Toast"}`),
+		}, []string{"sling-not-started:gastown/Toast:gt-002"}},
+		{"sling started", []model.Event{
+			ev(50*time.Minute, "sling", "mayor", `{"bead":"gt-002","target":"gastown/polecats/Toast"}`),
+			ev(48*time.Minute, "in_progress", "Toast", `{"bead":"gt-002"}`),
+		}, nil},
+		{"sling within grace", []model.Event{
+			ev(10*time.Minute, "sling", "mayor", `{"bead":"gt-002"}`),
+		}, nil},
+		{"handoff without session", []model.Event{
+			ev(20*time.Minute, "handoff", "gastown/polecats/Toast", `{"subject":"context full"}`),
+		}, []string{"handoff-no-session:gastown/Toast:"}},
+		{"handoff picked up", []model.Event{
+			ev(20*time.Minute, "handoff", "gastown/polecats/Toast", `{}`),
+			ev(19*time.Minute, "session_start", "gastown/polecats/Toast", `{"role":"polecat"}`),
+		}, nil},
+		{"re-sling never started", []model.Event{
+			ev(90*time.Minute, "sling", "mayor", `{"bead":"gt-002","target":"gastown/polecats/Toast"}`),
+			ev(80*time.Minute, "in_progress", "Toast", `{"bead":"gt-002"}`),
+			ev(50*time.Minute, "sling", "mayor", `{"bead":"gt-002","target":"gastown/polecats/Nux"}`),
+		}, []string{"sling-not-started:gastown/Nux:gt-002"}},
+		{"second handoff without session", []model.Event{
+			ev(40*time.Minute, "handoff", "gastown/polecats/Toast", `{}`),
+			ev(39*time.Minute, "session_start", "gastown/polecats/Toast", `{"role":"polecat"}`),
+			ev(20*time.Minute, "handoff", "gastown/polecats/Toast", `{}`),
+		}, []string{"handoff-no-session:gastown/Toast:"}},
+	}
+
+	d := NewDetector(30)
+	for _, tt := range tests {
+		var got []string
+		for _, a := range d.DetectAnomalies(tt.events, now) {
+			got = append(got, a.Kind+":"+a.Agent+":"+a.Bead)
+			if a.Reason == "" || len(a.Events) == 0 {
+				t.Errorf("%s: anomaly %s has no reason or events", tt.name, a.Kind)
+			}
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEventAnomaliesMarkStuck(t *testing.T) {
+	now := time.Now()
+	crash := func(ago time.Duration) model.Event {
+		e := model.Event{Timestamp: now.Add(-ago), Type: "crash", Actor: "gastown/polecats/Toast", Payload: []byte(`{"exit_code":137}`)}
+		e.ParsePayload()
+		return e
+	}
+	sling := model.Event{Timestamp: now.Add(-time.Hour), Type: "sling", Actor: "mayor",
+		Payload: []byte(`{"bead":"gt-002","target":"gastown/polecats/Toast"}`)}
+	sling.ParsePayload()
+
+	d := NewDetector(30)
+	d.ObserveEvents([]model.Event{sling, crash(6 * time.Minute), crash(2 * time.Minute)})
+
+	polecats := []model.Polecat{
+		{Name: "Toast", Rig: "gastown", State: "working", AssignedBead: "gt-002", Running: true, LastActivity: now},
+		{Name: "Nux", Rig: "gastown", State: "working", AssignedBead: "gt-003", Running: true, LastActivity: now},
+	}
+	d.CheckPolecats(polecats)
+	if !polecats[0].Stuck || polecats[0].StuckLevel != model.SeverityCritical {
+		t.Errorf("Toast: stuck=%v level=%q, want critical crash loop", polecats[0].Stuck, polecats[0].StuckLevel)
+	}
+	if !strings.Contains(polecats[0].StuckReason, "Crashed 2×") || !strings.Contains(polecats[0].StuckReason, "exit 137") {
+		t.Errorf("Toast: reason %q should reference the crashes", polecats[0].StuckReason)
+	}
+	if polecats[1].Stuck {
+		t.Errorf("Nux: unexpectedly stuck: %s", polecats[1].StuckReason)
+	}
+
+	beads := []model.Bead{
+		{ID: "gt-002", Status: "hooked", UpdatedAt: now},
+		{ID: "gt-002", Status: "in_progress", UpdatedAt: now},
+	}
+	d.CheckBeads(beads)
+	if !beads[0].Stuck || !strings.Contains(beads[0].StuckReason, "Slung to gastown/polecats/Toast at") {
+		t.Errorf("hooked bead: stuck=%v reason %q, want sling anomaly", beads[0].Stuck, beads[0].StuckReason)
+	}
+	if beads[1].Stuck {
+		t.Errorf("started bead: unexpectedly stuck: %s", beads[1].StuckReason)
+	}
+}
+
+func TestStuckHistory(t *testing.T) {
+	start := time.Date(2026, 1, 22, 12, 0, 0, 0, time.UTC)
+	now := start
+	h := NewHistory(HistoryConfig{ClearAfter: time.Minute, CriticalAfter: time.Hour})
+	h.now = func() time.Time { return now }
+
+	check := func(stuck bool, status model.BeadStatus) model.Bead {
+		beads := []model.Bead{{ID: "gt-1", Status: status}}
+		if stuck {
+			beads[0].Stuck = true
+			beads[0].StuckReason = "No updates for 30m"
+			beads[0].StuckLevel = model.SeverityWarning
+		}
+		h.ApplyBeads(beads)
+		return beads[0]
+	}
+
+	steps := []struct {
+		at        time.Duration // since start
+		stuck     bool
+		status    model.BeadStatus
+		wantStuck bool
+		wantLevel model.Severity
+		wantSince time.Duration
+		episodes  int
+	}{
+		{0, true, "in_progress", true, model.SeverityWarning, 0, 1},
+		{10 * time.Minute, true, "in_progress", true, model.SeverityWarning, 0, 1},
+		// A clean check inside the clear window is held stuck
+		{10*time.Minute + 30*time.Second, false, "in_progress", true, model.SeverityWarning, 0, 1},
+		{10*time.Minute + 50*time.Second, true, "in_progress", true, model.SeverityWarning, 0, 1},
+		// Escalates once stuck for CriticalAfter
+		{time.Hour, true, "in_progress", true, model.SeverityCritical, 0, 1},
+		// Clean for longer than the window since last seen clears it
+		{time.Hour + 30*time.Second, false, "in_progress", true, model.SeverityCritical, 0, 1},
+		{time.Hour + 4*time.Minute, false, "in_progress", false, model.SeverityNone, -1, 1},
+		// A new episode starts over at its own severity
+		{2 * time.Hour, true, "in_progress", true, model.SeverityWarning, 2 * time.Hour, 2},
+		// Closing clears immediately
+		{2*time.Hour + time.Second, false, "closed", false, model.SeverityNone, -1, 2},
+	}
+
+	for i, s := range steps {
+		now = start.Add(s.at)
+		got := check(s.stuck, s.status)
+		if got.Stuck != s.wantStuck || got.StuckLevel != s.wantLevel {
+			t.Errorf("step %d: stuck=%v level=%q, want %v %q", i, got.Stuck, got.StuckLevel, s.wantStuck, s.wantLevel)
+		}
+		if s.wantSince >= 0 && !got.StuckSince.Equal(start.Add(s.wantSince)) {
+			t.Errorf("step %d: since %v, want %v", i, got.StuckSince, start.Add(s.wantSince))
+		}
+		if s.wantSince < 0 && !got.StuckSince.IsZero() {
+			t.Errorf("step %d: since %v, want zero", i, got.StuckSince)
+		}
+		if st, _ := h.Get(EntityBead, "gt-1"); st.Episodes != s.episodes {
+			t.Errorf("step %d: %d episodes, want %d", i, st.Episodes, s.episodes)
+		}
+	}
+
+	if st, _ := h.Get(EntityBead, "gt-1"); st.Active() || !st.ClearedAt.Equal(now) {
+		t.Errorf("closed bead: active=%v cleared %v, want cleared at %v", st.Active(), st.ClearedAt, now)
+	}
+
+	now = now.Add(25 * time.Hour)
+	h.ApplyBeads(nil)
+	if _, ok := h.Get(EntityBead, "gt-1"); ok {
+		t.Error("expected old state to be pruned")
+	}
+}
+
+func TestDetectorCheckConvoys(t *testing.T) {
+	d := NewDetector(30)
+	now := time.Now()
+
+	beads := []model.Bead{
+		{ID: "gt-1", Status: "in_progress"},
+		{ID: "gt-2", Status: "in_progress", Stuck: true, StuckReason: "No updates for 45m", StuckLevel: model.SeverityWarning},
+		// Outside the listed page, fetched for the convoy
+		{ID: "bd-3", Status: "in_progress", Stuck: true, StuckReason: "No updates for 3h", StuckLevel: model.SeverityCritical},
+		{ID: "gt-4", Status: "in_progress"},
+	}
+	polecats := []model.Polecat{
+		{Name: "Toast", Rig: "gastown", HookedBead: "gt-4", Stuck: true, StuckReason: "Session not running"},
+	}
+	convoys := []model.Convoy{
+		{ID: "hq-1", Status: "open", TrackedIDs: []string{"gt-1", "gt-2", "bd-3"}, UpdatedAt: now},
+		{ID: "hq-2", Status: "open", TrackedIDs: []string{"gt-1", "gt-4"}, UpdatedAt: now},
+		{ID: "hq-3", Status: "open", TrackedIDs: []string{"gt-1"}, UpdatedAt: now},
+		{ID: "hq-4", Status: "closed", TrackedIDs: []string{"gt-2"}, UpdatedAt: now},
+	}
+
+	d.CheckConvoys(convoys, beads, polecats)
+
+	tests := []struct {
+		stuck  bool
+		reason string
+		cause  []string
+	}{
+		{true, "Tracked bd-3 stuck: No updates for 3h, +1 more", []string{"bd-3", "gt-2"}},
+		{true, "Swarm polecat gastown/Toast stuck: Session not running", nil},
+		{false, "", nil},
+		{false, "", []string{"gt-2"}},
+	}
+	for i, tt := range tests {
+		c := convoys[i]
+		if c.Stuck != tt.stuck || c.StuckReason != tt.reason {
+			t.Errorf("%s: stuck=%v reason %q, want %v %q", c.ID, c.Stuck, c.StuckReason, tt.stuck, tt.reason)
+		}
+		if strings.Join(c.StuckBeads, ",") != strings.Join(tt.cause, ",") {
+			t.Errorf("%s: stuck beads %v, want %v", c.ID, c.StuckBeads, tt.cause)
+		}
+	}
+}
+
+func TestSnoozes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "towns", "gt", "snoozes.json")
+	s, err := LoadSnoozes(path)
+	if err != nil {
+		t.Fatalf("loading missing file: %v", err)
+	}
+	now := time.Date(2026, 1, 22, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	updated := now.Add(-time.Hour)
+	bead := model.Bead{ID: "gt-1", Status: "in_progress", UpdatedAt: updated, Stuck: true, StuckReason: "No updates for 1h"}
+	polecat := model.Polecat{Name: "Toast", Rig: "gastown", State: "working", Running: false, Stuck: true}
+	convoy := model.Convoy{ID: "hq-1", Status: "open", ClosedCount: 1, TotalCount: 3, Stuck: true}
+
+	if err := s.SnoozeBead(&bead, 0); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+	if err := s.SnoozePolecat(&polecat, time.Hour); err != nil {
+		t.Fatalf("snooze: %v", err)
+	}
+	if err := s.SnoozeConvoy(&convoy, 0); err != nil {
+		t.Fatalf("ack: %v", err)
+	}
+
+	// Snoozes persist per town
+	s, err = LoadSnoozes(path)
+	if err != nil {
+		t.Fatalf("reloading: %v", err)
+	}
+	s.now = func() time.Time { return now }
+	if sn, ok := s.Get(EntityBead, "gt-1"); !ok || sn.Reason != "No updates for 1h" || sn.Describe(now) != "until it changes" {
+		t.Errorf("bead snooze after reload = %+v, %v", sn, ok)
+	}
+
+	beads := []model.Bead{bead}
+	polecats := []model.Polecat{polecat}
+	convoys := []model.Convoy{convoy}
+	s.ApplyBeads(beads)
+	s.ApplyPolecats(polecats)
+	s.ApplyConvoys(convoys)
+	if !beads[0].Snoozed || !polecats[0].Snoozed || !convoys[0].Snoozed {
+		t.Fatalf("expected all snoozed: %v %v %v", beads[0].Snoozed, polecats[0].Snoozed, convoys[0].Snoozed)
+	}
+
+	// A change to the bead ends its ack; unrelated churn doesn't
+	beads[0].StuckReason = "No updates for 2h"
+	now = now.Add(30 * time.Minute)
+	if s.ApplyBeads(beads); !beads[0].Snoozed {
+		t.Error("ack ended without the bead changing")
+	}
+	beads[0].UpdatedAt = now
+	if s.ApplyBeads(beads); beads[0].Snoozed {
+		t.Error("ack survived the bead being updated")
+	}
+
+	// The timed snooze lasts regardless of changes, until it expires
+	polecats[0].Running = true
+	if s.ApplyPolecats(polecats); !polecats[0].Snoozed {
+		t.Error("timed snooze ended early")
+	}
+	now = now.Add(31 * time.Minute)
+	if s.ApplyPolecats(polecats); polecats[0].Snoozed {
+		t.Error("timed snooze outlived its duration")
+	}
+
+	if err := s.Remove(EntityConvoy, "hq-1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	s, _ = LoadSnoozes(path)
+	for _, id := range []string{"bead:gt-1", "polecat:gastown/Toast", "convoy:hq-1"} {
+		entity, key, _ := strings.Cut(id, ":")
+		if _, ok := s.Get(entity, key); ok {
+			t.Errorf("%s still snoozed after reload", id)
+		}
+	}
+}
+
+func TestSnoozesShared(t *testing.T) {
+	// The TUI and gastop watch each hold a Snoozes for the same file
+	path := filepath.Join(t.TempDir(), "snoozes.json")
+	tui, _ := LoadSnoozes(path)
+	watch, _ := LoadSnoozes(path)
+	now := time.Date(2026, 1, 22, 12, 0, 0, 0, time.UTC)
+	tui.now = func() time.Time { return now }
+	watch.now = func() time.Time { return now }
+
+	bead := model.Bead{ID: "gt-1", Status: "in_progress", Stuck: true}
+	polecat := model.Polecat{Name: "Toast", Rig: "gastown", State: "working", Stuck: true}
+	convoy := model.Convoy{ID: "hq-1", Status: "open", Stuck: true}
+
+	if err := tui.SnoozeBead(&bead, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := watch.Get(EntityBead, "gt-1"); !ok {
+		t.Error("watch didn't see the ack made in the TUI")
+	}
+	beads := []model.Bead{bead}
+	if watch.ApplyBeads(beads); !beads[0].Snoozed {
+		t.Error("watch didn't apply the ack made in the TUI")
+	}
+
+	// Writes merge with the file instead of replacing it
+	if err := watch.SnoozePolecat(&polecat, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := tui.SnoozeConvoy(&convoy, 0); err != nil {
+		t.Fatal(err)
+	}
+	fresh, _ := LoadSnoozes(path)
+	for _, key := range [][2]string{{EntityBead, "gt-1"}, {EntityPolecat, "gastown/Toast"}, {EntityConvoy, "hq-1"}} {
+		if _, ok := fresh.Get(key[0], key[1]); !ok {
+			t.Errorf("%s %s lost from the file", key[0], key[1])
+		}
+	}
+
+	// Dropping an expired snooze leaves the other process's snoozes alone
+	now = now.Add(2 * time.Hour)
+	polecats := []model.Polecat{polecat}
+	if watch.ApplyPolecats(polecats); polecats[0].Snoozed {
+		t.Error("expired snooze still applied")
+	}
+	if _, ok := tui.Get(EntityPolecat, "gastown/Toast"); ok {
+		t.Error("TUI still sees the dropped snooze")
+	}
+	if _, ok := tui.Get(EntityConvoy, "hq-1"); !ok {
+		t.Error("dropping the expired snooze erased the convoy ack")
+	}
 }
@@ -0,0 +1,278 @@
+package stuck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Anomaly kinds detected from the event log.
+const (
+	AnomalyCrashLoop        = "crash-loop"
+	AnomalySlingNotStarted  = "sling-not-started"
+	AnomalyHandoffNoSession = "handoff-no-session"
+)
+
+// EventConfig tunes sequence-based detection over the event log. It is
+// read from the [stuck.events] table of the config file.
+type EventConfig struct {
+	CrashLoopCount  int           `toml:"crash_loop_count"`  // crashes within the window (default 2)
+	CrashLoopWindow time.Duration `toml:"crash_loop_window"` // default 10m
+	SlingGrace      time.Duration `toml:"sling_grace"`       // default: the base threshold
+	HandoffGrace    time.Duration `toml:"handoff_grace"`     // default 5m
+	Disabled        bool          `toml:"disabled"`
+}
+
+// withDefaults fills unset values.
+func (c EventConfig) withDefaults(threshold time.Duration) EventConfig {
+	if c.CrashLoopCount <= 1 {
+		c.CrashLoopCount = 2
+	}
+	if c.CrashLoopWindow <= 0 {
+		c.CrashLoopWindow = 10 * time.Minute
+	}
+	if c.SlingGrace <= 0 {
+		c.SlingGrace = threshold
+	}
+	if c.HandoffGrace <= 0 {
+		c.HandoffGrace = 5 * time.Minute
+	}
+	return c
+}
+
+// Anomaly is a suspicious sequence of events, such as repeated crashes.
+type Anomaly struct {
+	Kind     string
+	Agent    string // normalized rig/name of the agent involved, if any
+	Bead     string // bead involved, if any
+	Severity model.Severity
+	Reason   string
+	Events   []model.Event // the triggering events, oldest first
+}
+
+// Event types that show a slung bead was picked up.
+var startedTypes = map[string]bool{
+	"in_progress": true,
+	"completed":   true,
+	"done":        true,
+	"failed":      true,
+	"delete":      true,
+}
+
+// DetectAnomalies scans events (oldest first) for crash loops, slings
+// that were never started and handoffs with no following session.
+func (d *Detector) DetectAnomalies(events []model.Event, now time.Time) []Anomaly {
+	cfg := d.cfg.Events.withDefaults(d.cfg.Threshold)
+	if cfg.Disabled {
+		return nil
+	}
+
+	var out []Anomaly
+	crashes := make(map[string][]model.Event)
+	slings := make(map[string]model.Event)   // bead -> latest unstarted sling
+	handoffs := make(map[string]model.Event) // agent -> latest unanswered handoff
+	var crashOrder, slingOrder, handoffOrder []string
+	// Slings and handoffs are dropped once answered and may come back, so
+	// their order is tracked separately to list each key once
+	ordered := make(map[string]bool)
+
+	for _, e := range events {
+		c := e.PayloadData().Common()
+		agent := EventAgent(e)
+		switch e.Type {
+		case "crash":
+			if agent == "" {
+				continue
+			}
+			if _, ok := crashes[agent]; !ok {
+				crashOrder = append(crashOrder, agent)
+			}
+			crashes[agent] = append(crashes[agent], e)
+		case "sling":
+			if c.Bead == "" {
+				continue
+			}
+			if !ordered["sling:"+c.Bead] {
+				ordered["sling:"+c.Bead] = true
+				slingOrder = append(slingOrder, c.Bead)
+			}
+			slings[c.Bead] = e
+		case "handoff":
+			if agent == "" {
+				continue
+			}
+			if !ordered["handoff:"+agent] {
+				ordered["handoff:"+agent] = true
+				handoffOrder = append(handoffOrder, agent)
+			}
+			handoffs[agent] = e
+		case "session_start":
+			delete(handoffs, agent)
+		}
+		if startedTypes[e.Type] && c.Bead != "" {
+			delete(slings, c.Bead)
+		}
+	}
+
+	for _, agent := range crashOrder {
+		if a, ok := crashLoop(agent, crashes[agent], cfg, d.cfg.Threshold, now); ok {
+			out = append(out, a)
+		}
+	}
+	for _, bead := range slingOrder {
+		e, ok := slings[bead]
+		if !ok || now.Sub(e.Timestamp) <= cfg.SlingGrace {
+			continue
+		}
+		reason := "Slung at " + e.TimeString()
+		if target := e.PayloadData().Common().Target; target != "" {
+			reason = "Slung to " + target + " at " + e.TimeString()
+		}
+		out = append(out, Anomaly{
+			Kind:     AnomalySlingNotStarted,
+			Agent:    normalizeAgent(e.PayloadData().Common().Target),
+			Bead:     bead,
+			Severity: model.SeverityWarning,
+			Reason:   fmt.Sprintf("%s, not started after %s", reason, humanizeDuration(now.Sub(e.Timestamp))),
+			Events:   []model.Event{e},
+		})
+	}
+	for _, agent := range handoffOrder {
+		e, ok := handoffs[agent]
+		if !ok || now.Sub(e.Timestamp) <= cfg.HandoffGrace {
+			continue
+		}
+		reason := "Handed off at " + e.TimeString()
+		if p, ok := e.PayloadData().(*model.HandoffPayload); ok && p.To != "" {
+			reason = "Handed off to " + p.To + " at " + e.TimeString()
+		}
+		out = append(out, Anomaly{
+			Kind:     AnomalyHandoffNoSession,
+			Agent:    agent,
+			Bead:     e.PayloadData().Common().Bead,
+			Severity: model.SeverityWarning,
+			Reason:   fmt.Sprintf("%s, no new session after %s", reason, humanizeDuration(now.Sub(e.Timestamp))),
+			Events:   []model.Event{e},
+		})
+	}
+	return out
+}
+
+// crashLoop reports the densest run of crashes within the window, if it
+// reaches the configured count and is recent enough to still matter.
+func crashLoop(agent string, crashes []model.Event, cfg EventConfig, recent time.Duration, now time.Time) (Anomaly, bool) {
+	var best []model.Event
+	start := 0
+	for end := range crashes {
+		for crashes[end].Timestamp.Sub(crashes[start].Timestamp) > cfg.CrashLoopWindow {
+			start++
+		}
+		if run := crashes[start : end+1]; len(run) >= len(best) {
+			best = run
+		}
+	}
+	if len(best) < cfg.CrashLoopCount {
+		return Anomaly{}, false
+	}
+	last := best[len(best)-1]
+	if now.Sub(last.Timestamp) > recent {
+		return Anomaly{}, false
+	}
+
+	span := last.Timestamp.Sub(best[0].Timestamp)
+	reason := fmt.Sprintf("Crashed %d× in %s (last at %s: %s)",
+		len(best), humanizeDuration(span), last.TimeString(), last.Summary())
+	if span < time.Minute {
+		reason = fmt.Sprintf("Crashed %d× within a minute (last at %s: %s)",
+			len(best), last.TimeString(), last.Summary())
+	}
+	return Anomaly{
+		Kind:     AnomalyCrashLoop,
+		Agent:    agent,
+		Bead:     last.PayloadData().Common().Bead,
+		Severity: model.SeverityCritical,
+		Reason:   reason,
+		Events:   append([]model.Event(nil), best...),
+	}, true
+}
+
+// EventAgent returns the normalized agent an event is about: the polecat
+// in the payload if there is one, otherwise the actor.
+func EventAgent(e model.Event) string {
+	c := e.PayloadData().Common()
+	if c.Polecat != "" {
+		if c.Rig != "" {
+			return c.Rig + "/" + c.Polecat
+		}
+		return c.Polecat
+	}
+	return normalizeAgent(e.Actor)
+}
+
+// normalizeAgent maps "gastown/polecats/Toast" to "gastown/Toast".
+func normalizeAgent(name string) string {
+	return strings.Replace(name, "/polecats/", "/", 1)
+}
+
+// ObserveEvents records anomalies from the event log, to be applied by
+// later CheckBeads and CheckPolecats calls.
+func (d *Detector) ObserveEvents(events []model.Event) {
+	anomalies := d.DetectAnomalies(events, time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.anomalies = anomalies
+}
+
+// Anomalies returns the anomalies from the last ObserveEvents call.
+func (d *Detector) Anomalies() []Anomaly {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.anomalies
+}
+
+// beadAnomaly returns the most severe anomaly involving a bead.
+func (d *Detector) beadAnomaly(b *model.Bead) (Anomaly, bool) {
+	var match Anomaly
+	found := false
+	for _, a := range d.Anomalies() {
+		// Crashes and handoffs are reported on the polecat instead
+		if a.Kind != AnomalySlingNotStarted || a.Bead != b.ID {
+			continue
+		}
+		// A bead the snapshot shows as started has been picked up, even
+		// if that event fell outside the tail we read
+		if !b.Status.Is(model.StatusOpen) && !b.Status.Is(model.StatusHooked) {
+			continue
+		}
+		if !found || a.Severity.Rank() > match.Severity.Rank() {
+			match, found = a, true
+		}
+	}
+	return match, found
+}
+
+// polecatAnomaly returns the most severe anomaly involving a polecat.
+func (d *Detector) polecatAnomaly(p *model.Polecat) (Anomaly, bool) {
+	var match Anomaly
+	found := false
+	for _, a := range d.Anomalies() {
+		if a.Kind == AnomalySlingNotStarted || !matchesPolecat(a.Agent, p) {
+			continue
+		}
+		if !found || a.Severity.Rank() > match.Severity.Rank() {
+			match, found = a, true
+		}
+	}
+	return match, found
+}
+
+// matchesPolecat reports whether a normalized agent name refers to p.
+func matchesPolecat(agent string, p *model.Polecat) bool {
+	if agent == "" {
+		return false
+	}
+	return agent == p.Name || agent == p.Rig+"/"+p.Name
+}
@@ -0,0 +1,192 @@
+package stuck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// HistoryConfig tunes how stuck state is tracked across refreshes. It is
+// read from the [stuck.history] table of the config file.
+type HistoryConfig struct {
+	// ClearAfter is how long an item must check clean before it stops
+	// being stuck, so items near a threshold don't flap (default 1m).
+	ClearAfter time.Duration `toml:"clear_after"`
+	// CriticalAfter escalates an item to critical once it has been stuck
+	// this long (default 2h).
+	CriticalAfter time.Duration `toml:"critical_after"`
+	// Retain is how long state is kept for items no longer seen or long
+	// since cleared (default 24h).
+	Retain time.Duration `toml:"retain"`
+}
+
+// withDefaults fills unset values.
+func (c HistoryConfig) withDefaults() HistoryConfig {
+	if c.ClearAfter <= 0 {
+		c.ClearAfter = time.Minute
+	}
+	if c.CriticalAfter <= 0 {
+		c.CriticalAfter = 2 * time.Hour
+	}
+	if c.Retain <= 0 {
+		c.Retain = 24 * time.Hour
+	}
+	return c
+}
+
+// State is the stuck history of one bead, polecat or convoy.
+type State struct {
+	Since     time.Time // start of the current stuck episode; zero if not stuck
+	LastSeen  time.Time // last check that found the item stuck
+	ClearedAt time.Time // end of the previous episode
+	Episodes  int       // number of times the item has become stuck
+	Reason    string
+	Level     model.Severity
+}
+
+// Active reports whether the item is in a stuck episode.
+func (s *State) Active() bool {
+	return !s.Since.IsZero()
+}
+
+// History tracks stuck state transitions per entity across refreshes.
+// Applying it after a Detector check holds items stuck until they have
+// been clean for a while, records how long they've been stuck, and
+// escalates long-stuck items to critical.
+type History struct {
+	cfg HistoryConfig
+	now func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*State
+}
+
+// NewHistory creates an empty stuck history.
+func NewHistory(cfg HistoryConfig) *History {
+	return &History{
+		cfg:    cfg.withDefaults(),
+		now:    time.Now,
+		states: make(map[string]*State),
+	}
+}
+
+// ApplyBeads updates the history from checked beads and adjusts their
+// stuck fields.
+func (h *History) ApplyBeads(beads []model.Bead) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	for i := range beads {
+		b := &beads[i]
+		// Finished work is never held stuck
+		if b.Status.IsDone() {
+			h.clear(EntityBead+":"+b.ID, now)
+			continue
+		}
+		h.apply(EntityBead+":"+b.ID, now, &b.Stuck, &b.StuckReason, &b.StuckLevel, &b.StuckSince)
+	}
+	h.prune(now)
+}
+
+// ApplyPolecats updates the history from checked polecats and adjusts
+// their stuck fields.
+func (h *History) ApplyPolecats(polecats []model.Polecat) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	for i := range polecats {
+		p := &polecats[i]
+		h.apply(EntityPolecat+":"+p.FullName(), now, &p.Stuck, &p.StuckReason, &p.StuckLevel, &p.StuckSince)
+	}
+	h.prune(now)
+}
+
+// ApplyConvoys updates the history from checked convoys and adjusts their
+// stuck fields.
+func (h *History) ApplyConvoys(convoys []model.Convoy) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	for i := range convoys {
+		c := &convoys[i]
+		if c.Status.IsDone() {
+			h.clear(EntityConvoy+":"+c.ID, now)
+			continue
+		}
+		h.apply(EntityConvoy+":"+c.ID, now, &c.Stuck, &c.StuckReason, &c.StuckLevel, &c.StuckSince)
+	}
+	h.prune(now)
+}
+
+// Get returns the history of an entity ("bead", "polecat" or "convoy")
+// by ID, or rig/name for polecats.
+func (h *History) Get(entity, id string) (State, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.states[entity+":"+id]; ok {
+		return *s, true
+	}
+	return State{}, false
+}
+
+// apply records one check of an item and rewrites its stuck fields.
+func (h *History) apply(key string, now time.Time, stuck *bool, reason *string, level *model.Severity, since *time.Time) {
+	s := h.states[key]
+
+	if *stuck {
+		if s == nil {
+			s = &State{}
+			h.states[key] = s
+		}
+		if !s.Active() {
+			s.Since = now
+			s.Episodes++
+		}
+		s.LastSeen = now
+		s.Reason = *reason
+		s.Level = *level
+	} else {
+		if s == nil || !s.Active() {
+			return
+		}
+		if now.Sub(s.LastSeen) >= h.cfg.ClearAfter {
+			s.Since = time.Time{}
+			s.ClearedAt = now
+			return
+		}
+		// Still within the clear window: keep reporting the last reason
+		*stuck = true
+		*reason = s.Reason
+	}
+
+	*level = s.Level
+	if *level == model.SeverityNone {
+		*level = model.SeverityWarning
+	}
+	if now.Sub(s.Since) >= h.cfg.CriticalAfter {
+		*level = model.SeverityCritical
+	}
+	*since = s.Since
+}
+
+// clear ends any stuck episode immediately.
+func (h *History) clear(key string, now time.Time) {
+	if s, ok := h.states[key]; ok && s.Active() {
+		s.Since = time.Time{}
+		s.ClearedAt = now
+	}
+}
+
+// prune drops state for items not seen or not stuck within Retain.
+func (h *History) prune(now time.Time) {
+	for key, s := range h.states {
+		last := s.LastSeen
+		if s.ClearedAt.After(last) {
+			last = s.ClearedAt
+		}
+		if now.Sub(last) > h.cfg.Retain {
+			delete(h.states, key)
+		}
+	}
+}
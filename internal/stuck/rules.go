@@ -0,0 +1,433 @@
+package stuck
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Entities a rule can apply to.
+const (
+	EntityBead    = "bead"
+	EntityPolecat = "polecat"
+	EntityConvoy  = "convoy"
+)
+
+// Config configures stuck detection. It is read from the [stuck] table of
+// the config file.
+type Config struct {
+	// Threshold is the base stuck threshold, referenced in rules as
+	// "threshold". Per-rig and per-priority values override it, with
+	// priority taking precedence.
+	Threshold          time.Duration            `toml:"threshold"`
+	RigThresholds      map[string]time.Duration `toml:"rig_thresholds"`      // rig name -> threshold
+	PriorityThresholds map[string]time.Duration `toml:"priority_thresholds"` // "P0".."P4" -> threshold
+
+	// Rules are added to the built-in rules. A rule with the same name as
+	// a built-in replaces it.
+	Rules          []Rule `toml:"rules"`
+	NoBuiltinRules bool   `toml:"no_builtin_rules"`
+
+	// Events tunes detection of anomalies in the event log.
+	Events EventConfig `toml:"events"`
+
+	// History tunes hysteresis and escalation across refreshes.
+	History HistoryConfig `toml:"history"`
+}
+
+// Rule flags an entity as stuck when all of its conditions hold.
+//
+// Conditions have the form "<field> <op> <value>", with op one of
+// == != > >= < <= or "in" (comma-separated values). A value is a literal
+// (string, number, bool, duration like "45m", or empty quotes), another
+// field, or "threshold", optionally scaled like "2*threshold". The reason
+// may reference fields as {field}.
+type Rule struct {
+	Name     string         `toml:"name"`
+	Entity   string         `toml:"entity"` // bead, polecat, convoy
+	When     []string       `toml:"when"`
+	Severity model.Severity `toml:"severity"` // warning (default), critical
+	Reason   string         `toml:"reason"`
+	Disabled bool           `toml:"disabled"`
+
+	conds []condition
+}
+
+// BuiltinRules returns the default rules.
+func BuiltinRules() []Rule {
+	return []Rule{
+		{
+			Name:   "molecule-step-overdue",
+			Entity: EntityBead,
+			When:   []string{"step_elapsed > 2*step_typical"},
+			Reason: "Step {step} running {step_elapsed} (usually {step_typical})",
+		},
+		{
+			Name:   "bead-no-updates",
+			Entity: EntityBead,
+			When:   []string{"status == in_progress", "since_update > threshold"},
+			Reason: "No updates for {since_update}",
+		},
+		{
+			Name:   "bead-blocked",
+			Entity: EntityBead,
+			When:   []string{"status == blocked", "since_update > 2*threshold"},
+			Reason: "Blocked for {since_update}",
+		},
+		{
+			Name:   "polecat-marked-stuck",
+			Entity: EntityPolecat,
+			When:   []string{"state == stuck"},
+			Reason: "Marked stuck by Gas Town",
+		},
+		{
+			Name:   "polecat-no-bead",
+			Entity: EntityPolecat,
+			When:   []string{"state == working", "assigned_bead == ''"},
+			Reason: "Working but no assigned bead",
+		},
+		{
+			Name:   "polecat-no-activity",
+			Entity: EntityPolecat,
+			When:   []string{"state == working", "since_activity > threshold"},
+			Reason: "No activity for {since_activity}",
+		},
+		{
+			Name:   "polecat-session-down",
+			Entity: EntityPolecat,
+			When:   []string{"state == working", "running == false"},
+			Reason: "Session not running",
+		},
+		{
+			Name:   "convoy-stuck-beads",
+			Entity: EntityConvoy,
+			When:   []string{"status != closed", "stuck_beads > 0"},
+			Reason: "Tracked {stuck_bead} stuck: {stuck_bead_reason}{stuck_more}",
+		},
+		{
+			Name:   "convoy-stuck-polecats",
+			Entity: EntityConvoy,
+			When:   []string{"status != closed", "stuck_polecats > 0"},
+			Reason: "Swarm polecat {stuck_polecat} stuck: {stuck_polecat_reason}",
+		},
+		{
+			Name:   "convoy-no-progress",
+			Entity: EntityConvoy,
+			When:   []string{"status != closed", "since_update > 2*threshold"},
+			Reason: "No progress for {since_update}",
+		},
+	}
+}
+
+// kind is the type of a field value.
+type kind int
+
+const (
+	kindString kind = iota
+	kindNumber
+	kindBool
+	kindDuration
+)
+
+// fieldKinds lists the fields each entity exposes to rules.
+var fieldKinds = map[string]map[string]kind{
+	EntityBead: {
+		"id": kindString, "status": kindString, "type": kindString,
+		"priority": kindNumber, "assignee": kindString, "rig": kindString,
+		"since_update": kindDuration, "since_created": kindDuration,
+		"blockers": kindNumber,
+		// Molecules only
+		"step": kindString, "step_elapsed": kindDuration, "step_typical": kindDuration,
+	},
+	EntityPolecat: {
+		"name": kindString, "rig": kindString, "state": kindString,
+		"assigned_bead": kindString, "hooked_bead": kindString,
+		"running": kindBool, "attached": kindBool,
+		"since_activity": kindDuration, "since_created": kindDuration,
+	},
+	EntityConvoy: {
+		"id": kindString, "status": kindString, "tracked": kindNumber,
+		"since_update": kindDuration,
+		// The worst stuck tracked bead and swarm polecat, if any.
+		// stuck_more is ", +N more" when several beads are stuck.
+		"stuck_beads": kindNumber, "stuck_bead": kindString,
+		"stuck_bead_reason": kindString, "stuck_more": kindString,
+		"stuck_polecats": kindNumber, "stuck_polecat": kindString,
+		"stuck_polecat_reason": kindString,
+	},
+}
+
+// facts holds an entity's field values. Fields that don't apply (e.g. a
+// duration since a zero time) are absent, and conditions on them fail.
+type facts map[string]interface{}
+
+// operand is the right-hand side of a condition.
+type operand struct {
+	factor float64
+	ref    string      // Field name or "threshold"
+	lit    interface{} // Literal value when ref is empty
+	list   []string    // Values for "in"
+}
+
+// condition is a parsed "<field> <op> <value>".
+type condition struct {
+	field string
+	op    string
+	rhs   operand
+}
+
+var condPattern = regexp.MustCompile(`^\s*(\w+)\s*(==|!=|>=|<=|>|<|\bin\b)\s*(.*?)\s*$`)
+
+// compile parses and type-checks the rule's conditions.
+func (r *Rule) compile() error {
+	fields, ok := fieldKinds[r.Entity]
+	if !ok {
+		return fmt.Errorf("rule %q: unknown entity %q", r.Name, r.Entity)
+	}
+	if len(r.When) == 0 {
+		return fmt.Errorf("rule %q: no conditions", r.Name)
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = model.SeverityWarning
+	case model.SeverityWarning, model.SeverityCritical:
+	default:
+		return fmt.Errorf("rule %q: unknown severity %q", r.Name, r.Severity)
+	}
+
+	r.conds = nil
+	for _, w := range r.When {
+		c, err := parseCondition(w, fields)
+		if err != nil {
+			return fmt.Errorf("rule %q: %q: %w", r.Name, w, err)
+		}
+		r.conds = append(r.conds, c)
+	}
+	return nil
+}
+
+// parseCondition parses a condition against an entity's fields.
+func parseCondition(s string, fields map[string]kind) (condition, error) {
+	m := condPattern.FindStringSubmatch(s)
+	if m == nil {
+		return condition{}, fmt.Errorf("expected <field> <op> <value>")
+	}
+	c := condition{field: m[1], op: m[2]}
+	lhs, ok := fields[c.field]
+	if !ok {
+		return c, fmt.Errorf("unknown field %q", c.field)
+	}
+
+	if c.op == "in" {
+		if lhs != kindString {
+			return c, fmt.Errorf("%q is not a string field", c.field)
+		}
+		for _, v := range strings.Split(m[3], ",") {
+			c.rhs.list = append(c.rhs.list, strings.TrimSpace(v))
+		}
+		return c, nil
+	}
+
+	rhs, rk, err := parseOperand(m[3], fields)
+	if err != nil {
+		return c, err
+	}
+	c.rhs = rhs
+	if rk != lhs {
+		return c, fmt.Errorf("cannot compare %q with %q", c.field, m[3])
+	}
+	if (lhs == kindString || lhs == kindBool) && c.op != "==" && c.op != "!=" {
+		return c, fmt.Errorf("%q only supports == and !=", c.field)
+	}
+	return c, nil
+}
+
+// parseOperand parses a value, returning its kind.
+func parseOperand(s string, fields map[string]kind) (operand, kind, error) {
+	op := operand{factor: 1}
+	if f, rest, ok := strings.Cut(s, "*"); ok {
+		factor, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return op, 0, fmt.Errorf("bad factor %q", f)
+		}
+		op.factor = factor
+		s = strings.TrimSpace(rest)
+	}
+
+	if s == "threshold" {
+		op.ref = s
+		return op, kindDuration, nil
+	}
+	if k, ok := fields[s]; ok {
+		op.ref = s
+		return op, k, nil
+	}
+	if op.factor != 1 {
+		return op, 0, fmt.Errorf("can only scale thresholds and fields, not %q", s)
+	}
+
+	if d, err := time.ParseDuration(s); err == nil && s != "0" {
+		op.lit = d
+		return op, kindDuration, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		op.lit = n
+		return op, kindNumber, nil
+	}
+	if b, err := strconv.ParseBool(s); err == nil && (s == "true" || s == "false") {
+		op.lit = b
+		return op, kindBool, nil
+	}
+	op.lit = strings.Trim(s, `'"`)
+	return op, kindString, nil
+}
+
+// eval reports whether the condition holds for the facts.
+func (c *condition) eval(f facts, threshold time.Duration) bool {
+	lhs, ok := f[c.field]
+	if !ok {
+		return false
+	}
+
+	if c.op == "in" {
+		for _, v := range c.rhs.list {
+			if lhs == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	rhs := c.rhs.lit
+	switch c.rhs.ref {
+	case "":
+	case "threshold":
+		rhs = threshold
+	default:
+		if rhs, ok = f[c.rhs.ref]; !ok {
+			return false
+		}
+	}
+
+	switch l := lhs.(type) {
+	case time.Duration:
+		r := time.Duration(float64(rhs.(time.Duration)) * c.rhs.factor)
+		return compare(c.op, float64(l), float64(r))
+	case float64:
+		return compare(c.op, l, rhs.(float64)*c.rhs.factor)
+	default:
+		switch c.op {
+		case "==":
+			return lhs == rhs
+		case "!=":
+			return lhs != rhs
+		}
+	}
+	return false
+}
+
+// compare applies an ordering operator.
+func compare(op string, l, r float64) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	}
+	return false
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// reason expands {field} placeholders in the rule's reason.
+func (r *Rule) reason(f facts, threshold time.Duration) string {
+	return placeholder.ReplaceAllStringFunc(r.Reason, func(m string) string {
+		name := m[1 : len(m)-1]
+		var v interface{} = threshold
+		if name != "threshold" {
+			var ok bool
+			if v, ok = f[name]; !ok {
+				return m
+			}
+		}
+		switch v := v.(type) {
+		case time.Duration:
+			return humanizeDuration(v)
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return fmt.Sprint(v)
+		}
+	})
+}
+
+// overlay returns the rule with the fields set in o replaced, so a
+// configured rule can tune a built-in without restating it.
+func (r Rule) overlay(o Rule) Rule {
+	if o.Entity != "" {
+		r.Entity = o.Entity
+	}
+	if len(o.When) > 0 {
+		r.When = o.When
+	}
+	if o.Severity != "" {
+		r.Severity = o.Severity
+	}
+	if o.Reason != "" {
+		r.Reason = o.Reason
+	}
+	if o.Disabled {
+		r.Disabled = true
+	}
+	return r
+}
+
+// compileRules merges configured rules over the built-ins and compiles them.
+func compileRules(cfg Config) ([]Rule, error) {
+	var rules []Rule
+	if !cfg.NoBuiltinRules {
+		rules = BuiltinRules()
+	}
+	for _, r := range cfg.Rules {
+		merged := false
+		for i := range rules {
+			if rules[i].Name == r.Name {
+				rules[i] = rules[i].overlay(r)
+				merged = true
+			}
+		}
+		if !merged {
+			rules = append(rules, r)
+		}
+	}
+
+	compiled := rules[:0]
+	for _, r := range rules {
+		if r.Disabled {
+			continue
+		}
+		if err := r.compile(); err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled, nil
+}
+
+// Validate checks that the configured rules parse.
+func (c Config) Validate() error {
+	_, err := compileRules(c)
+	return err
+}
@@ -0,0 +1,314 @@
+package stuck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// Snooze silences a stuck bead, polecat or convoy. A snooze with an Until
+// time lasts until then; one without (an acknowledgement) lasts until the
+// item changes.
+type Snooze struct {
+	Entity      string    `json:"entity"`
+	ID          string    `json:"id"` // rig/name for polecats
+	Until       time.Time `json:"until,omitempty"`
+	Fingerprint string    `json:"fingerprint,omitempty"` // item state when acknowledged
+	Reason      string    `json:"reason,omitempty"`      // stuck reason when snoozed, for reference
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Expired reports whether a timed snooze has run out.
+func (s *Snooze) Expired(now time.Time) bool {
+	return !s.Until.IsZero() && !now.Before(s.Until)
+}
+
+// Describe returns a short description such as "for 45m" or "until it
+// changes".
+func (s *Snooze) Describe(now time.Time) string {
+	if s.Until.IsZero() {
+		return "until it changes"
+	}
+	return "for " + humanizeDuration(s.Until.Sub(now))
+}
+
+// Snoozes is the set of snoozed items for one town, persisted as JSON. The
+// TUI and gastop watch share the file, so it is re-read when it changes and
+// each write merges into what is on disk under a lock file.
+type Snoozes struct {
+	path string
+	now  func() time.Time
+
+	mu    sync.Mutex
+	items map[string]Snooze
+	stat  os.FileInfo // of the file as last read or written
+}
+
+const (
+	lockWait  = 2 * time.Second  // How long a write waits for the lock
+	lockStale = 10 * time.Second // Age at which a left-over lock is broken
+)
+
+// LoadSnoozes reads snoozes from path. A missing file is an empty set.
+func LoadSnoozes(path string) (*Snoozes, error) {
+	s := &Snoozes{path: path, now: time.Now, items: make(map[string]Snooze)}
+	return s, s.load()
+}
+
+// load re-reads the file if another process has replaced it since it was
+// last read. On error the snoozes already read are kept. Callers hold s.mu.
+func (s *Snoozes) load() error {
+	if s.path == "" {
+		return nil
+	}
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if s.stat != nil {
+			s.items = make(map[string]Snooze)
+			s.stat = nil
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.stat != nil && os.SameFile(info, s.stat) && info.ModTime().Equal(s.stat.ModTime()) && info.Size() == s.stat.Size() {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var list []Snooze
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	items := make(map[string]Snooze, len(list))
+	for _, sn := range list {
+		items[sn.Entity+":"+sn.ID] = sn
+	}
+	s.items = items
+	s.stat = info
+	return nil
+}
+
+// lock takes the lock file beside the snoozes file, waiting for another
+// process to release it and breaking one left behind by a process that
+// died. The returned func releases it.
+func (s *Snoozes) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, err
+	}
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is held by another gastop", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// update re-reads the file under the lock, applies change and saves, so
+// snoozes written by another process meanwhile are kept. Callers hold s.mu.
+func (s *Snoozes) update(change func()) error {
+	if s.path == "" {
+		change()
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	change()
+	return s.save()
+}
+
+// save writes the snoozes atomically. Callers hold s.mu and the lock.
+func (s *Snoozes) save() error {
+	list := make([]Snooze, 0, len(s.items))
+	for _, sn := range s.items {
+		list = append(list, sn)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Entity != list[j].Entity {
+			return list[i].Entity < list[j].Entity
+		}
+		return list[i].ID < list[j].ID
+	})
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.stat = info
+	}
+	return nil
+}
+
+// Get returns the snooze for an item, if any, re-reading the file if it
+// has changed.
+func (s *Snoozes) Get(entity, id string) (Snooze, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load() // On error, answer from the snoozes already read
+	sn, ok := s.items[entity+":"+id]
+	return sn, ok
+}
+
+// SnoozeBead snoozes a bead for d, or until it changes if d is zero.
+func (s *Snoozes) SnoozeBead(b *model.Bead, d time.Duration) error {
+	return s.add(EntityBead, b.ID, beadFingerprint(b), b.StuckReason, d)
+}
+
+// SnoozePolecat snoozes a polecat for d, or until it changes if d is zero.
+func (s *Snoozes) SnoozePolecat(p *model.Polecat, d time.Duration) error {
+	return s.add(EntityPolecat, p.FullName(), polecatFingerprint(p), p.StuckReason, d)
+}
+
+// SnoozeConvoy snoozes a convoy for d, or until it changes if d is zero.
+func (s *Snoozes) SnoozeConvoy(c *model.Convoy, d time.Duration) error {
+	return s.add(EntityConvoy, c.ID, convoyFingerprint(c), c.StuckReason, d)
+}
+
+// add records a snooze and saves.
+func (s *Snoozes) add(entity, id, fingerprint, reason string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	sn := Snooze{Entity: entity, ID: id, Reason: reason, CreatedAt: now}
+	if d > 0 {
+		sn.Until = now.Add(d)
+	} else {
+		sn.Fingerprint = fingerprint
+	}
+	return s.update(func() { s.items[entity+":"+id] = sn })
+}
+
+// Remove ends a snooze early.
+func (s *Snoozes) Remove(entity, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update(func() { delete(s.items, entity+":"+id) })
+}
+
+// ApplyBeads marks snoozed beads, dropping snoozes that have expired or
+// whose bead has changed.
+func (s *Snoozes) ApplyBeads(beads []model.Bead) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.load()
+	dropped := make(map[string]Snooze)
+	for i := range beads {
+		b := &beads[i]
+		b.Snoozed = s.check(EntityBead+":"+b.ID, beadFingerprint(b), dropped)
+	}
+	return errors.Join(err, s.drop(dropped))
+}
+
+// ApplyPolecats marks snoozed polecats, dropping snoozes that have expired
+// or whose polecat has changed.
+func (s *Snoozes) ApplyPolecats(polecats []model.Polecat) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.load()
+	dropped := make(map[string]Snooze)
+	for i := range polecats {
+		p := &polecats[i]
+		p.Snoozed = s.check(EntityPolecat+":"+p.FullName(), polecatFingerprint(p), dropped)
+	}
+	return errors.Join(err, s.drop(dropped))
+}
+
+// ApplyConvoys marks snoozed convoys, dropping snoozes that have expired
+// or whose convoy has changed.
+func (s *Snoozes) ApplyConvoys(convoys []model.Convoy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.load()
+	dropped := make(map[string]Snooze)
+	for i := range convoys {
+		c := &convoys[i]
+		c.Snoozed = s.check(EntityConvoy+":"+c.ID, convoyFingerprint(c), dropped)
+	}
+	return errors.Join(err, s.drop(dropped))
+}
+
+// check reports whether an item is snoozed. A snooze that has expired or
+// whose item has changed is removed and added to dropped.
+func (s *Snoozes) check(key, fingerprint string, dropped map[string]Snooze) bool {
+	sn, ok := s.items[key]
+	if !ok {
+		return false
+	}
+	if sn.Expired(s.now()) || (sn.Fingerprint != "" && sn.Fingerprint != fingerprint) {
+		delete(s.items, key)
+		dropped[key] = sn
+		return false
+	}
+	return true
+}
+
+// drop removes ended snoozes from the file, leaving any that another
+// process has replaced since. Callers hold s.mu.
+func (s *Snoozes) drop(dropped map[string]Snooze) error {
+	if len(dropped) == 0 {
+		return nil
+	}
+	return s.update(func() {
+		for key, sn := range dropped {
+			if cur, ok := s.items[key]; ok && cur.CreatedAt.Equal(sn.CreatedAt) {
+				delete(s.items, key)
+			}
+		}
+	})
+}
+
+// beadFingerprint captures the parts of a bead whose change ends an
+// acknowledgement. Fingerprints leave out fields that move on their own,
+// such as ages and the durations in stuck reasons.
+func beadFingerprint(b *model.Bead) string {
+	return fmt.Sprintf("%s|%s|%d|%s", b.Status.Canonical(), b.Assignee, b.Priority, b.UpdatedAt.UTC().Format(time.RFC3339))
+}
+
+// polecatFingerprint captures a polecat's state and work.
+func polecatFingerprint(p *model.Polecat) string {
+	return fmt.Sprintf("%s|%s|%s|%t", p.State.Canonical(), p.AssignedBead, p.HookedBead, p.Running)
+}
+
+// convoyFingerprint captures a convoy's status and progress.
+func convoyFingerprint(c *model.Convoy) string {
+	return fmt.Sprintf("%s|%d/%d", c.Status.Canonical(), c.ClosedCount, c.TotalCount)
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/audit"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Audit actions for the TUI's own commands.
+const (
+	actionNudge = "nudge"
+	actionSpawn = "spawn"
+	actionSling = "sling"
+)
+
+// nudgeSelected asks for a message to send to the selected polecat.
+func (a *App) nudgeSelected() {
+	if a.app.GetFocus() != a.polecats.Primitive() {
+		return
+	}
+	pc := a.polecats.Selected()
+	if pc == nil {
+		return
+	}
+	polecat := *pc
+
+	form := tview.NewForm()
+	form.AddInputField("Message:", "", 48, nil, nil)
+	input := form.GetFormItemByLabel("Message:").(*tview.InputField)
+	send := func() {
+		msg := strings.TrimSpace(input.GetText())
+		if msg == "" {
+			return
+		}
+		a.closeOverlay()
+		a.runAction(audit.Entry{Action: actionNudge, Target: polecat.FullName(), Bead: polecat.CurrentBead()},
+			"Nudged "+polecat.FullName(),
+			func(ctx context.Context) error {
+				return a.adapter.NudgePolecat(ctx, polecat.Rig, polecat.Name, msg)
+			})
+	}
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			send()
+		}
+	})
+	form.AddButton("Nudge", send)
+	form.AddButton("Cancel", a.closeOverlay)
+	form.SetCancelFunc(a.closeOverlay)
+	form.SetBorder(true).SetTitle(" Nudge " + polecat.FullName() + " ")
+
+	a.showOverlay(form, 62, 7)
+}
+
+// showSlingPicker offers the rigs and polecats the selected bead can be
+// slung to. Slinging to a rig spawns a fresh polecat for it.
+func (a *App) showSlingPicker() {
+	if a.app.GetFocus() != a.beads.Primitive() {
+		return
+	}
+	b := a.beads.Selected()
+	if b == nil {
+		return
+	}
+	if b.Status.Is(model.StatusClosed) {
+		a.showMessage(b.ID + " is closed")
+		return
+	}
+	beadID := b.ID
+
+	a.mu.RLock()
+	polecats := a.polecatData
+	a.mu.RUnlock()
+	rigs := a.pickerRigs()
+	if len(rigs) == 0 && len(polecats) == 0 {
+		a.showMessage("No rigs or polecats known yet (waiting for gt status)")
+		return
+	}
+
+	list := tview.NewList()
+	for _, rig := range rigs {
+		list.AddItem(rig, "New polecat in "+rig, pickerShortcut(list.GetItemCount()), func() {
+			a.closeOverlay()
+			a.runAction(audit.Entry{Action: actionSling, Target: rig, Bead: beadID},
+				"Slung "+beadID+" to "+rig,
+				func(ctx context.Context) error {
+					return a.adapter.SlingBead(ctx, beadID, rig)
+				})
+		})
+	}
+	for i := range polecats {
+		pc := polecats[i]
+		secondary := string(pc.State)
+		if bead := pc.CurrentBead(); bead != "" {
+			secondary += " · " + bead
+		}
+		list.AddItem(pc.FullName(), secondary, pickerShortcut(list.GetItemCount()), func() {
+			a.closeOverlay()
+			a.slingToPolecat(beadID, pc)
+		})
+	}
+
+	a.showPicker(list, " Sling "+beadID+" ")
+}
+
+// slingToPolecat slings a bead onto a polecat's hook.
+func (a *App) slingToPolecat(beadID string, pc model.Polecat) {
+	a.runAction(audit.Entry{Action: actionSling, Target: pc.FullName(), Bead: beadID},
+		"Slung "+beadID+" to "+pc.FullName(),
+		func(ctx context.Context) error {
+			return a.adapter.SlingBeadToPolecat(ctx, beadID, pc.Rig, pc.Name)
+		})
+}
+
+// showSpawnPicker offers the rigs a new polecat can be spawned in.
+func (a *App) showSpawnPicker() {
+	rigs := a.pickerRigs()
+	if len(rigs) == 0 {
+		a.showMessage("No rigs known yet (waiting for gt status)")
+		return
+	}
+
+	list := tview.NewList()
+	for i, rig := range rigs {
+		list.AddItem(rig, "", pickerShortcut(i), func() {
+			a.closeOverlay()
+			a.runAction(audit.Entry{Action: actionSpawn, Target: rig},
+				"Spawned a polecat in "+rig,
+				func(ctx context.Context) error {
+					return a.adapter.SpawnPolecat(ctx, rig)
+				})
+		})
+	}
+	list.ShowSecondaryText(false)
+
+	a.showPicker(list, " Spawn Polecat ")
+}
+
+// pickerRigs returns the rigs to offer in action pickers: the focused rig
+// alone, else every rig in gt status.
+func (a *App) pickerRigs() []string {
+	if rig := a.engine.Rig(); rig != "" {
+		return []string{rig}
+	}
+	status := a.engine.TownStatus()
+	if status == nil {
+		return nil
+	}
+	rigs := make([]string, 0, len(status.Rigs))
+	for _, rig := range status.Rigs {
+		rigs = append(rigs, rig.Name)
+	}
+	return rigs
+}
+
+// pickerShortcut returns the shortcut for the i-th picker item: 1-9,
+// then none.
+func pickerShortcut(i int) rune {
+	if i < 9 {
+		return rune('1' + i)
+	}
+	return 0
+}
+
+// showPicker shows a list overlay sized to its items, closed with Esc.
+func (a *App) showPicker(list *tview.List, title string) {
+	list.SetBorder(true).SetTitle(title)
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeOverlay()
+			return nil
+		}
+		return event
+	})
+
+	rows := list.GetItemCount()
+	if _, secondary := list.GetItemText(0); secondary != "" {
+		rows *= 2
+	}
+	a.showOverlay(list, 44, min(rows, 20)+2)
+}
+
+// runAction runs a gt action in the background, records it in the audit
+// log and reports the outcome.
+func (a *App) runAction(entry audit.Entry, done string, fn func(ctx context.Context) error) {
+	go func() {
+		err := fn(a.ctx)
+
+		entry.Source = audit.SourceUser
+		entry.Result = audit.ResultOK
+		if err != nil {
+			entry.Result = audit.ResultError
+			entry.Error = err.Error()
+		}
+		if auditErr := a.engine.Audit().Record(entry); auditErr != nil {
+			a.setError("audit log: " + auditErr.Error())
+		}
+
+		a.app.QueueUpdateDraw(func() {
+			if err != nil {
+				a.showMessage(fmt.Sprintf("Failed to %s %s: %v", entry.Action, entry.Target, err))
+				return
+			}
+			a.showMessage(done)
+			go a.refresh()
+		})
+	}()
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/rivo/tview"
+)
+
+// AgentsPanel displays the town's infrastructure agents.
+type AgentsPanel struct {
+	view   *tview.TextView
+	agents []model.Agent
+}
+
+// NewAgentsPanel creates a new agents panel.
+func NewAgentsPanel() *AgentsPanel {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetTitle(" AGENTS ").
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	return &AgentsPanel{view: view}
+}
+
+// Primitive returns the tview primitive.
+func (p *AgentsPanel) Primitive() tview.Primitive {
+	return p.view
+}
+
+// Update updates the panel with new agent data.
+func (p *AgentsPanel) Update(agents []model.Agent) {
+	tags := GetTags()
+	p.agents = agents
+
+	var b strings.Builder
+	for _, ag := range agents {
+		var color string
+		switch {
+		case ag.IsDown():
+			color = tags.Error
+		case ag.Running:
+			color = tags.Success
+		default:
+			color = tags.Idle
+		}
+
+		line := fmt.Sprintf("[%s]%s[-] %-18s", color, ag.StateIcon(), ag.FullName())
+		if ag.SessionID != "" {
+			line += " [" + tags.Dim + "]" + truncate(ag.SessionID, 20) + "[-]"
+		}
+		if up := ag.Uptime(); up != "" {
+			line += " [" + tags.Muted + "]up " + up + "[-]"
+		}
+		if ag.IsDown() {
+			line += " [" + tags.Error + "::b]DOWN[::-][-]"
+		} else if !ag.Running {
+			line += " [" + tags.Muted + "]stopped[-]"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(agents) == 0 {
+		fmt.Fprintf(&b, "[%s]Waiting for gt status...[-]\n", tags.Muted)
+	}
+
+	if down := p.DownCount(); down > 0 {
+		p.view.SetTitle(fmt.Sprintf(" AGENTS [%s]⚠ %d down[-] ", tags.Error, down))
+	} else {
+		p.view.SetTitle(" AGENTS ")
+	}
+	p.view.SetText(b.String())
+}
+
+// DownCount returns the number of required agents that are not running.
+func (p *AgentsPanel) DownCount() int {
+	n := 0
+	for i := range p.agents {
+		if p.agents[i].IsDown() {
+			n++
+		}
+	}
+	return n
+}
+
+// ScrollDown scrolls the view down one line.
+func (p *AgentsPanel) ScrollDown() {
+	row, col := p.view.GetScrollOffset()
+	p.view.ScrollTo(row+1, col)
+}
+
+// ScrollUp scrolls the view up one line.
+func (p *AgentsPanel) ScrollUp() {
+	row, col := p.view.GetScrollOffset()
+	if row > 0 {
+		p.view.ScrollTo(row-1, col)
+	}
+}
+
+// ScrollToTop scrolls to the top of the view.
+func (p *AgentsPanel) ScrollToTop() {
+	p.view.ScrollToBeginning()
+}
+
+// ScrollToBottom scrolls to the bottom of the view.
+func (p *AgentsPanel) ScrollToBottom() {
+	p.view.ScrollToEnd()
+}
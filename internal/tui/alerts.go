@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// screenTerminal rings the bell and sets the title on the TUI's screen.
+type screenTerminal struct {
+	a      *App
+	screen tcell.Screen // Captured on draw; only touched from the UI goroutine
+}
+
+// newScreenTerminal creates the terminal for bell and title alerts.
+func newScreenTerminal(a *App) *screenTerminal {
+	t := &screenTerminal{a: a}
+	a.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		t.screen = screen
+		return false
+	})
+	return t
+}
+
+// Beep implements alert.Terminal.
+func (t *screenTerminal) Beep() error {
+	t.a.app.QueueUpdate(func() {
+		if t.screen != nil {
+			_ = t.screen.Beep()
+		}
+	})
+	return nil
+}
+
+// SetTitle implements alert.Terminal.
+func (t *screenTerminal) SetTitle(title string) {
+	t.a.app.SetTitle(title)
+}
@@ -2,12 +2,18 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/davidsenack/gastop/internal/adapter"
 	"github.com/davidsenack/gastop/internal/config"
+	"github.com/davidsenack/gastop/internal/engine"
+	"github.com/davidsenack/gastop/internal/graph"
 	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/refinery"
 	"github.com/davidsenack/gastop/internal/stuck"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -21,7 +27,9 @@ type App struct {
 	app     *tview.Application
 	adapter *adapter.Adapter
 	config  *config.Config
+	engine  *engine.Engine // Refresh, stuck detection and alerts
 	stuck   *stuck.Detector
+	snoozes *stuck.Snoozes
 
 	// Layout
 	layout      *tview.Flex
@@ -32,6 +40,8 @@ type App struct {
 	beads       *BeadsPanel
 	polecats    *PolecatsPanel
 	events      *EventsPanel
+	refinery    *RefineryPanel
+	agents      *AgentsPanel
 
 	// Panel tracking for vim navigation
 	panels       []tview.Primitive
@@ -47,13 +57,24 @@ type App struct {
 	beadData         []model.Bead
 	polecatData      []model.Polecat
 	eventData        []model.Event
-	townStatus       *adapter.TownStatus
-	currentRig       string
+	mergeQueues      []refinery.Queue
+	agentData        []model.Agent
 	autoRefresh      bool
 	showLogs         bool
+	showRefinery     bool
+	showAgents       bool
 	lastError        string
 	lastRefresh      time.Time
-	beadStatusFilter string // Filter beads by status ("" = all)
+	refreshing       atomic.Bool  // A refresh is in flight
+	refreshPending   atomic.Bool  // Another refresh was requested meanwhile
+	beadStatusFilter string       // Filter beads by status ("" = all)
+	beadMode         beadMode     // All, ready or blocked beads
+	queueData        []model.Bead // Ready or blocked queue, outside beadModeAll
+
+	// Convoy drill-down: beads and polecats panels show only this convoy's
+	// tracked beads and swarm until Esc
+	convoyFocus *model.Convoy
+	convoyExtra []model.Bead // Tracked beads outside the current bead list
 
 	// Context for background operations
 	ctx    context.Context
@@ -68,7 +89,6 @@ func NewApp(cfg *config.Config, adp *adapter.Adapter) *App {
 		app:          tview.NewApplication(),
 		adapter:      adp,
 		config:       cfg,
-		stuck:        stuck.NewDetector(cfg.StuckThresholdMins),
 		autoRefresh:  true,
 		showLogs:     cfg.ShowLogs,
 		ctx:          ctx,
@@ -77,6 +97,16 @@ func NewApp(cfg *config.Config, adp *adapter.Adapter) *App {
 		keyHandlers:  make(map[tcell.Key]keyHandler),
 	}
 
+	eng, err := engine.New(cfg, adp, newScreenTerminal(a))
+	if err != nil {
+		// Start with no snoozes rather than refusing to run
+		a.lastError = err.Error()
+	}
+	eng.OnError = a.setError
+	a.engine = eng
+	a.stuck = eng.Detector()
+	a.snoozes = eng.Snoozes()
+
 	a.setupUI()
 	a.registerKeyBindings()
 	a.setupInputCapture()
@@ -93,17 +123,8 @@ func (a *App) setupUI() {
 	a.beads = NewBeadsPanel()
 	a.polecats = NewPolecatsPanel()
 	a.events = NewEventsPanel(a.config.LogLines)
-
-	// Track panels for vim navigation (h/l)
-	a.panels = []tview.Primitive{
-		a.convoys.Primitive(),
-		a.beads.Primitive(),
-		a.polecats.Primitive(),
-	}
-	if a.showLogs {
-		a.panels = append(a.panels, a.events.Primitive())
-	}
-	a.currentPanel = 0
+	a.refinery = NewRefineryPanel()
+	a.agents = NewAgentsPanel()
 
 	// Wire up selection handlers
 	a.convoys.SetSelectedFunc(func(convoy *model.Convoy) {
@@ -112,6 +133,16 @@ func (a *App) setupUI() {
 			a.filterBeadsByConvoy(convoy)
 		}
 	})
+	a.beads.SetSelectedFunc(func(b *model.Bead) {
+		if b != nil {
+			a.showBeadDetail(b)
+		}
+	})
+	a.polecats.SetSelectedFunc(func(pc *model.Polecat) {
+		if pc != nil {
+			a.showPolecatDetail(pc)
+		}
+	})
 
 	// Create main content area (3 columns)
 	a.mainContent = tview.NewFlex().SetDirection(tview.FlexColumn).
@@ -119,19 +150,61 @@ func (a *App) setupUI() {
 		AddItem(a.beads.Primitive(), 0, 2, false).
 		AddItem(a.polecats.Primitive(), 0, 1, false)
 
-	// Main layout with help bar at bottom
-	a.layout = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(a.statusBar.Primitive(), 1, 0, false).
-		AddItem(a.mainContent, 0, 1, true)
+	a.layout = tview.NewFlex().SetDirection(tview.FlexRow)
+	a.buildLayout()
+	a.currentPanel = 0
 
-	if a.showLogs {
-		a.layout.AddItem(a.events.Primitive(), a.config.LogLines+2, 0, false)
+	a.app.SetRoot(a.layout, true)
+}
+
+// buildLayout (re)assembles the layout and the h/l panel order from the
+// currently visible panels.
+func (a *App) buildLayout() {
+	a.mu.RLock()
+	showLogs := a.showLogs
+	showRefinery := a.showRefinery
+	showAgents := a.showAgents
+	a.mu.RUnlock()
+
+	// Track panels for vim navigation (h/l)
+	a.panels = []tview.Primitive{
+		a.convoys.Primitive(),
+		a.beads.Primitive(),
+		a.polecats.Primitive(),
+	}
+
+	a.layout.Clear()
+	a.layout.AddItem(a.statusBar.Primitive(), 1, 0, false)
+	a.layout.AddItem(a.mainContent, 0, 1, true)
+
+	// Bottom row holds the optional events, refinery and agents panels
+	bottom := tview.NewFlex().SetDirection(tview.FlexColumn)
+	hasBottom := false
+	if showLogs {
+		bottom.AddItem(a.events.Primitive(), 0, 2, false)
+		a.panels = append(a.panels, a.events.Primitive())
+		hasBottom = true
+	}
+	if showRefinery {
+		bottom.AddItem(a.refinery.Primitive(), 0, 1, false)
+		a.panels = append(a.panels, a.refinery.Primitive())
+		hasBottom = true
+	}
+	if showAgents {
+		bottom.AddItem(a.agents.Primitive(), 0, 1, false)
+		a.panels = append(a.panels, a.agents.Primitive())
+		hasBottom = true
+	}
+	if hasBottom {
+		a.layout.AddItem(bottom, a.config.LogLines+2, 0, false)
 	}
 
 	// Add help bar at the very bottom
 	a.layout.AddItem(a.helpBar.Primitive(), 1, 0, false)
 
-	a.app.SetRoot(a.layout, true)
+	if a.currentPanel >= len(a.panels) {
+		a.currentPanel = 0
+	}
 }
 
 // registerKeyBindings registers all key bindings.
@@ -139,17 +212,28 @@ func (a *App) registerKeyBindings() {
 	// Special keys
 	a.keyHandlers[tcell.KeyTab] = a.focusNext
 	a.keyHandlers[tcell.KeyBacktab] = a.focusPrev
+	a.keyHandlers[tcell.KeyEscape] = a.clearConvoyFocus
 
 	// Application control
 	a.runeHandlers['q'] = a.Stop
 	a.runeHandlers['r'] = func() { go a.refresh() }
 	a.runeHandlers['t'] = a.toggleAutoRefresh
 	a.runeHandlers['L'] = a.toggleLogs
+	a.runeHandlers['M'] = a.toggleRefinery
+	a.runeHandlers['A'] = a.toggleAgents
+	a.runeHandlers['T'] = a.toggleBeadTree
+	a.runeHandlers[' '] = a.toggleBeadCollapse
+	a.runeHandlers['m'] = a.cycleBeadMode
 
 	// Dialogs
 	a.runeHandlers['?'] = a.showHelp
 	a.runeHandlers['/'] = a.showSearch
 	a.runeHandlers['f'] = a.showFilter
+	a.runeHandlers['R'] = a.showRigPicker
+	a.runeHandlers['v'] = a.showGraphView
+	a.runeHandlers['C'] = a.showCapacityView
+	a.runeHandlers['a'] = a.ackSelected
+	a.runeHandlers['z'] = a.showSnoozePicker
 
 	// Refresh interval
 	a.runeHandlers['+'] = a.decreaseRefreshInterval
@@ -169,11 +253,21 @@ func (a *App) registerKeyBindings() {
 	// Kill/close action
 	a.runeHandlers['x'] = a.killSelected
 	a.runeHandlers['d'] = a.killSelected
+
+	// gt actions
+	a.runeHandlers['n'] = a.nudgeSelected
+	a.runeHandlers['s'] = a.showSlingPicker
+	a.runeHandlers['S'] = a.showSpawnPicker
 }
 
 // setupInputCapture configures the input capture handler.
 func (a *App) setupInputCapture() {
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Let overlays (search, filter, detail views) handle their own keys
+		if !a.mainFocused() {
+			return event
+		}
+
 		// Check special keys first
 		if handler, ok := a.keyHandlers[event.Key()]; ok {
 			handler()
@@ -192,6 +286,17 @@ func (a *App) setupInputCapture() {
 	})
 }
 
+// mainFocused returns true if one of the main panels has focus.
+func (a *App) mainFocused() bool {
+	focused := a.app.GetFocus()
+	for _, p := range a.panels {
+		if p == focused {
+			return true
+		}
+	}
+	return false
+}
+
 // focusNext moves focus to the next panel (vim l / Tab).
 func (a *App) focusNext() {
 	a.currentPanel = (a.currentPanel + 1) % len(a.panels)
@@ -221,6 +326,10 @@ func (a *App) updateHelpBarForFocus() {
 		a.helpBar.UpdateForPanel("polecats")
 	case a.events.Primitive():
 		a.helpBar.UpdateForPanel("events")
+	case a.refinery.Primitive():
+		a.helpBar.UpdateForPanel("refinery")
+	case a.agents.Primitive():
+		a.helpBar.UpdateForPanel("agents")
 	default:
 		a.helpBar.UpdateDefault()
 	}
@@ -241,6 +350,10 @@ func (a *App) navigateDown() {
 		a.polecats.list.SetCurrentItem(a.polecats.list.GetCurrentItem() + 1)
 	case a.events.Primitive():
 		a.events.ScrollDown()
+	case a.refinery.Primitive():
+		a.refinery.ScrollDown()
+	case a.agents.Primitive():
+		a.agents.ScrollDown()
 	}
 }
 
@@ -265,6 +378,10 @@ func (a *App) navigateUp() {
 		}
 	case a.events.Primitive():
 		a.events.ScrollUp()
+	case a.refinery.Primitive():
+		a.refinery.ScrollUp()
+	case a.agents.Primitive():
+		a.agents.ScrollUp()
 	}
 }
 
@@ -280,6 +397,10 @@ func (a *App) navigateTop() {
 		a.polecats.list.SetCurrentItem(0)
 	case a.events.Primitive():
 		a.events.ScrollToTop()
+	case a.refinery.Primitive():
+		a.refinery.ScrollToTop()
+	case a.agents.Primitive():
+		a.agents.ScrollToTop()
 	}
 }
 
@@ -295,6 +416,10 @@ func (a *App) navigateBottom() {
 		a.polecats.list.SetCurrentItem(a.polecats.list.GetItemCount() - 1)
 	case a.events.Primitive():
 		a.events.ScrollToBottom()
+	case a.refinery.Primitive():
+		a.refinery.ScrollToBottom()
+	case a.agents.Primitive():
+		a.agents.ScrollToBottom()
 	}
 }
 
@@ -380,6 +505,198 @@ func (a *App) showMessage(msg string) {
 	a.app.SetRoot(modal, true)
 }
 
+// showOverlay centers a primitive over the main layout.
+func (a *App) showOverlay(p tview.Primitive, width, height int) {
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(p, width, 0, true).
+			AddItem(nil, 0, 1, false), height, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	a.app.SetRoot(flex, true)
+}
+
+// closeOverlay returns to the main layout.
+func (a *App) closeOverlay() {
+	a.app.SetRoot(a.layout, true)
+	a.app.SetFocus(a.panels[a.currentPanel])
+}
+
+// showPolecatDetail opens the detail view for a polecat with its timeline
+// from the event log, then fills in gt polecat status, the hooked bead and
+// the worktree in the background.
+func (a *App) showPolecatDetail(pc *model.Polecat) {
+	polecat := *pc
+	a.mu.RLock()
+	timeline := polecat.Timeline(a.eventData)
+	a.mu.RUnlock()
+
+	detail := NewPolecatDetail()
+	detail.Update(&polecat, timeline, nil, nil)
+
+	detail.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeOverlay()
+			return nil
+		}
+		return event
+	})
+	a.showOverlay(detail.Primitive(), 90, 32)
+
+	go func() {
+		_ = a.adapter.EnrichPolecatWithDetails(a.ctx, &polecat)
+		if hook, err := a.adapter.GetHookedBead(a.ctx, polecat.FullName()); err == nil {
+			polecat.HookedBead, polecat.HookedTitle = hook.Bead, hook.Title
+		}
+		a.app.QueueUpdateDraw(func() {
+			detail.Update(&polecat, timeline, nil, nil)
+		})
+
+		wt, err := a.adapter.InspectWorktree(a.ctx, polecat.ClonePath)
+		a.app.QueueUpdateDraw(func() {
+			detail.Update(&polecat, timeline, wt, err)
+		})
+	}()
+}
+
+// showBeadDetail opens the detail view for a bead. It renders immediately
+// from the current data, then again once bd show returns its description
+// and dependencies. Enter on a link opens that bead; Esc goes back.
+func (a *App) showBeadDetail(b *model.Bead) {
+	detail := NewBeadDetail()
+	var back []string // Beads opened before the current one
+
+	var show func(bead model.Bead)
+	show = func(bead model.Bead) {
+		a.mu.RLock()
+		polecats, events := a.polecatData, a.eventData
+		a.mu.RUnlock()
+		detail.Update(&bead, a.buildGraph(&bead), polecats, events)
+
+		go func() {
+			fetched, err := a.adapter.GetBead(a.ctx, bead.ID)
+			if err != nil {
+				return
+			}
+			full := *fetched
+			full.Stuck, full.StuckReason = bead.Stuck, bead.StuckReason
+			full.StuckLevel, full.StuckSince = bead.StuckLevel, bead.StuckSince
+			full.Snoozed = bead.Snoozed
+			full.Molecule = bead.Molecule
+			g := a.buildGraph(&full)
+			a.app.QueueUpdateDraw(func() {
+				if detail.ID() == full.ID { // Not navigated away meanwhile
+					detail.Update(&full, g, polecats, events)
+				}
+			})
+		}()
+	}
+
+	detail.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if len(back) == 0 {
+				a.closeOverlay()
+				return nil
+			}
+			id := back[len(back)-1]
+			back = back[:len(back)-1]
+			show(a.knownBead(id))
+			return nil
+		case tcell.KeyTab:
+			detail.NextLink(1)
+			return nil
+		case tcell.KeyBacktab:
+			detail.NextLink(-1)
+			return nil
+		case tcell.KeyEnter:
+			if id := detail.SelectedLink(); id != "" {
+				back = append(back, detail.ID())
+				show(a.knownBead(id))
+			}
+			return nil
+		}
+		return event
+	})
+	a.showOverlay(detail.Primitive(), 90, 32)
+	show(*b)
+}
+
+// knownBead returns a bead from the current data, or a bead with only its
+// ID for bd show to fill in.
+func (a *App) knownBead(id string) model.Bead {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, list := range [][]model.Bead{a.beadData, a.queueData, a.convoyExtra} {
+		for i := range list {
+			if list[i].ID == id {
+				return list[i]
+			}
+		}
+	}
+	return model.Bead{ID: id}
+}
+
+// toggleBeadTree switches the beads panel between table and tree mode.
+func (a *App) toggleBeadTree() {
+	a.beads.ToggleTree()
+}
+
+// toggleBeadCollapse collapses or expands the selected parent bead.
+func (a *App) toggleBeadCollapse() {
+	if a.app.GetFocus() == a.beads.Primitive() {
+		a.beads.ToggleCollapse()
+	}
+}
+
+// showGraphView opens the full-screen dependency graph centered on the
+// selected bead.
+func (a *App) showGraphView() {
+	if a.app.GetFocus() != a.beads.Primitive() {
+		return
+	}
+	b := a.beads.Selected()
+	if b == nil {
+		return
+	}
+
+	view := NewGraphView()
+	view.SetGraph(a.buildGraph(nil), b.ID)
+	view.SetDoneFunc(a.closeOverlay)
+	a.app.SetRoot(view.Primitive(), true)
+	a.app.SetFocus(view.Primitive())
+}
+
+// buildGraph builds the dependency graph from the current beads and
+// convoys, optionally replacing one bead with fresher detail.
+func (a *App) buildGraph(override *model.Bead) *graph.Graph {
+	a.mu.RLock()
+	beads := make([]model.Bead, len(a.beadData))
+	copy(beads, a.beadData)
+	convoys := a.convoyData
+	a.mu.RUnlock()
+
+	if override != nil {
+		found := false
+		for i := range beads {
+			if beads[i].ID == override.ID {
+				beads[i] = *override
+				found = true
+			}
+		}
+		if !found {
+			beads = append(beads, *override)
+		}
+	}
+
+	g := graph.Build(beads)
+	g.AddConvoys(convoys)
+	return g
+}
+
 // toggleAutoRefresh toggles automatic refresh.
 func (a *App) toggleAutoRefresh() {
 	a.mu.Lock()
@@ -412,28 +729,37 @@ func (a *App) increaseRefreshInterval() {
 func (a *App) toggleLogs() {
 	a.mu.Lock()
 	a.showLogs = !a.showLogs
-	showLogs := a.showLogs
 	a.mu.Unlock()
 
-	// Update panels list
-	a.panels = []tview.Primitive{
-		a.convoys.Primitive(),
-		a.beads.Primitive(),
-		a.polecats.Primitive(),
-	}
-	if showLogs {
-		a.panels = append(a.panels, a.events.Primitive())
+	// Rebuild layout
+	a.app.QueueUpdateDraw(func() {
+		a.buildLayout()
+	})
+}
+
+// toggleRefinery toggles the merge queue panel visibility.
+func (a *App) toggleRefinery() {
+	a.mu.Lock()
+	a.showRefinery = !a.showRefinery
+	showRefinery := a.showRefinery
+	a.mu.Unlock()
+
+	a.app.QueueUpdateDraw(func() {
+		a.buildLayout()
+	})
+	if showRefinery {
+		go a.refreshMergeQueue()
 	}
+}
+
+// toggleAgents toggles the infrastructure agents panel visibility.
+func (a *App) toggleAgents() {
+	a.mu.Lock()
+	a.showAgents = !a.showAgents
+	a.mu.Unlock()
 
-	// Rebuild layout
 	a.app.QueueUpdateDraw(func() {
-		a.layout.Clear()
-		a.layout.AddItem(a.statusBar.Primitive(), 1, 0, false)
-		a.layout.AddItem(a.mainContent, 0, 1, true)
-		if showLogs {
-			a.layout.AddItem(a.events.Primitive(), a.config.LogLines+2, 0, false)
-		}
-		a.layout.AddItem(a.helpBar.Primitive(), 1, 0, false)
+		a.buildLayout()
 	})
 }
 
@@ -458,7 +784,7 @@ func (a *App) showHelp() {
 		AddItem(tview.NewFlex().
 			AddItem(nil, 0, 1, false).
 			AddItem(help, 50, 0, true).
-			AddItem(nil, 0, 1, false), 24, 0, true).
+			AddItem(nil, 0, 1, false), 39, 0, true).
 		AddItem(nil, 0, 1, false)
 
 	a.app.SetRoot(flex, true)
@@ -469,7 +795,13 @@ func (a *App) showSearch() {
 	searchModal := NewSearchModal(
 		func(query string) {
 			// Perform search and return to main view
-			a.beads.Search(query)
+			a.mu.RLock()
+			title := beadsTitle(a.beadMode, a.beadStatusFilter)
+			if a.convoyFocus != nil {
+				title = convoyTitle(a.convoyFocus)
+			}
+			a.mu.RUnlock()
+			a.beads.Search(query, title)
 			a.app.SetRoot(a.layout, true)
 			a.app.SetFocus(a.beads.Primitive())
 		},
@@ -538,11 +870,66 @@ func (a *App) showFilter() {
 	a.app.SetRoot(modal, true)
 }
 
+// showRigPicker displays the rig picker built from gt status.
+func (a *App) showRigPicker() {
+	status := a.engine.TownStatus()
+	current := a.engine.Rig()
+
+	if status == nil || len(status.Rigs) == 0 {
+		a.showMessage("No rigs known yet (waiting for gt status)")
+		return
+	}
+
+	list := tview.NewList().
+		AddItem("All rigs", "Show the whole town", 'a', func() {
+			a.setRig("")
+		})
+	for i, rig := range status.Rigs {
+		name := rig.Name
+		secondary := rig.State
+		if rig.Prefix != "" {
+			secondary += " · " + rig.Prefix + "-*"
+		}
+		var shortcut rune
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+		list.AddItem(name, secondary, shortcut, func() {
+			a.setRig(name)
+		})
+		if name == current {
+			list.SetCurrentItem(i + 1)
+		}
+	}
+
+	list.SetBorder(true).SetTitle(" Focus Rig ")
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeOverlay()
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay(list, 40, 2*len(status.Rigs)+4)
+}
+
+// setRig focuses the TUI on a rig ("" = all rigs) and refreshes.
+func (a *App) setRig(name string) {
+	a.engine.SetRig(name)
+
+	a.closeOverlay()
+	a.updateStatusBar()
+	go a.refresh()
+	go a.refreshTownStatus()
+}
+
 // applyBeadFilter applies a status filter to beads and returns to main layout.
 func (a *App) applyBeadFilter(status string) {
 	a.mu.Lock()
 	a.beadStatusFilter = status
-	beads := a.beadData
+	beads := a.modeBeads()
+	mode := a.beadMode
 	a.mu.Unlock()
 
 	// Filter beads
@@ -551,11 +938,7 @@ func (a *App) applyBeadFilter(status string) {
 	// Update panel
 	a.app.QueueUpdateDraw(func() {
 		a.beads.Update(filtered)
-		if status == "" {
-			a.beads.SetTitle("BEADS")
-		} else {
-			a.beads.SetTitle("BEADS [" + status + "]")
-		}
+		a.beads.SetTitle(beadsTitle(mode, status))
 		a.app.SetRoot(a.layout, true)
 	})
 }
@@ -567,131 +950,234 @@ func (a *App) filterBeadsByStatus(beads []model.Bead, status string) []model.Bea
 	}
 	var filtered []model.Bead
 	for _, b := range beads {
-		if b.Status == status {
+		if b.Status.Is(model.BeadStatus(status)) {
 			filtered = append(filtered, b)
 		}
 	}
 	return filtered
 }
 
-// filterBeadsByConvoy filters beads to show only those tracked by the convoy.
+// filterBeadsByConvoy drills down into a convoy: the beads panel shows its
+// tracked beads and the polecats panel its swarm until Esc. It renders from
+// the current data, then again once gt convoy status and any tracked beads
+// outside the current bead list have been fetched.
 func (a *App) filterBeadsByConvoy(convoy *model.Convoy) {
-	// For now, just update the title to show we're filtering
-	// Full implementation would filter beadData
-	a.beads.SetTitle("BEADS [" + convoy.ID + "]")
-}
-
-// refresh fetches new data from Gas Town.
-// Updates UI incrementally as each data source returns.
-func (a *App) refresh() {
+	c := *convoy
 	a.mu.Lock()
-	a.lastRefresh = time.Now()
+	a.convoyFocus = &c
+	a.convoyExtra = nil
 	a.mu.Unlock()
 
-	// Clear error and update status bar immediately (spinner tick)
-	a.mu.Lock()
-	a.lastError = ""
-	a.mu.Unlock()
-	a.app.QueueUpdateDraw(func() {
-		a.updateStatusBar()
-	})
+	a.showConvoyFocus()
+	a.currentPanel = 1 // Beads
+	a.app.SetFocus(a.beads.Primitive())
+	a.updateHelpBarForFocus()
 
-	// Fetch polecats (usually fastest)
 	go func() {
-		polecats, err := a.adapter.ListPolecats(a.ctx, "")
-		if err != nil {
-			a.mu.Lock()
-			a.lastError = "polecats: " + err.Error()
+		full, extra := a.engine.ConvoyBeads(a.ctx, c)
+
+		a.mu.Lock()
+		if a.convoyFocus == nil || a.convoyFocus.ID != c.ID {
 			a.mu.Unlock()
-			return // Use cached data
+			return // Drill-down was cleared or moved on
 		}
+		a.convoyFocus = &full
+		a.convoyExtra = extra
+		a.mu.Unlock()
 
-		// Enrich working polecats with hooked bead info and details
-		for i := range polecats {
-			if polecats[i].State == "working" {
-				// Fetch detailed status for working polecats
-				_ = a.adapter.EnrichPolecatWithDetails(a.ctx, &polecats[i])
-			}
+		a.app.QueueUpdateDraw(a.showConvoyFocus)
+	}()
+}
+
+// showConvoyFocus renders the convoy drill-down into the beads and polecats
+// panels. Must be called on the UI goroutine.
+func (a *App) showConvoyFocus() {
+	a.mu.RLock()
+	c := a.convoyFocus
+	beads := append(append([]model.Bead(nil), a.beadData...), a.convoyExtra...)
+	polecats := a.polecatData
+	a.mu.RUnlock()
+	if c == nil {
+		return
+	}
+
+	// Tracked beads in convoy order
+	byID := make(map[string]model.Bead, len(beads))
+	for _, b := range beads {
+		byID[b.ID] = b
+	}
+	var tracked []model.Bead
+	for _, id := range c.TrackedIDs {
+		if b, ok := byID[id]; ok {
+			tracked = append(tracked, b)
 		}
-		// Fetch hooked bead info (only for working/done polecats)
-		a.adapter.EnrichPolecatsWithHooks(a.ctx, polecats)
+	}
 
-		a.stuck.CheckPolecats(polecats)
+	a.beads.SetShowClosed(true)
+	a.beads.Update(tracked)
+	a.beads.SetTitle(convoyTitle(c))
+
+	swarm := c.Swarm(polecats)
+	a.polecats.Update(swarm)
+	a.polecats.SetTitle(fmt.Sprintf("SWARM [%s: %d]", c.ID, len(swarm)))
+}
+
+// convoyTitle returns the beads panel title for a convoy drill-down.
+func convoyTitle(c *model.Convoy) string {
+	title := fmt.Sprintf("BEADS [%s %s", c.ID, c.ProgressString())
+	if c.ClosedAt != nil {
+		title += ", landed " + c.ClosedAgo() + " ago"
+	}
+	if n := len(c.StuckBeads); n > 0 {
+		title += fmt.Sprintf(", %d stuck", n)
+	}
+	return title + "]"
+}
+
+// inConvoyFocus returns true while drilled down into a convoy.
+func (a *App) inConvoyFocus() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.convoyFocus != nil
+}
+
+// clearConvoyFocus leaves the convoy drill-down and restores the
+// unfiltered beads and polecats panels.
+func (a *App) clearConvoyFocus() {
+	a.mu.Lock()
+	if a.convoyFocus == nil {
+		a.mu.Unlock()
+		return
+	}
+	a.convoyFocus = nil
+	a.convoyExtra = nil
+	filter := a.beadStatusFilter
+	beads := a.modeBeads()
+	mode := a.beadMode
+	polecats := a.polecatData
+	a.mu.Unlock()
+
+	a.beads.SetShowClosed(false)
+	a.beads.Update(a.filterBeadsByStatus(beads, filter))
+	a.beads.SetTitle(beadsTitle(mode, filter))
+	a.polecats.Update(polecats)
+	a.polecats.SetTitle("POLECATS")
+}
+
+// refresh fetches new data from Gas Town, joins it into one snapshot,
+// runs stuck detection over it and updates the panels. A refresh requested
+// while one is in flight runs once the current one finishes. gt status is
+// too slow (~4s) for this loop; see townStatusLoop.
+func (a *App) refresh() {
+	if !a.refreshing.CompareAndSwap(false, true) {
+		a.refreshPending.Store(true)
+		return
+	}
+	defer a.refreshing.Store(false)
+
+	for {
 		a.mu.Lock()
-		a.polecatData = polecats
+		a.lastRefresh = time.Now()
+		a.lastError = ""
 		a.mu.Unlock()
+		// Update the status bar immediately (spinner tick)
 		a.app.QueueUpdateDraw(func() {
-			a.polecats.UpdateWithSpinner(polecats)
+			a.updateStatusBar()
 		})
-	}()
 
-	// Fetch beads
-	go func() {
-		beads, err := a.adapter.ListBeads(a.ctx, adapter.BeadListOpts{Limit: 100})
-		if err != nil {
-			return // Use cached data
+		a.applySnapshot(a.engine.Refresh(a.ctx))
+		a.refreshBeadQueue()
+		a.refreshMergeQueue()
+
+		if !a.refreshPending.Swap(false) {
+			return
 		}
-		a.stuck.CheckBeads(beads)
-		a.mu.Lock()
-		a.beadData = beads
-		filter := a.beadStatusFilter
-		a.mu.Unlock()
+	}
+}
 
-		// Apply current filter
-		filtered := a.filterBeadsByStatus(beads, filter)
+// refreshTownStatus fetches gt status and updates the agents panel.
+func (a *App) refreshTownStatus() {
+	agents, err := a.engine.RefreshTownStatus(a.ctx)
+	if err != nil {
+		return // Keep the last known status
+	}
 
-		a.app.QueueUpdateDraw(func() {
-			a.beads.Update(filtered)
-			if filter != "" {
-				a.beads.SetTitle("BEADS [" + filter + "]")
-			}
-		})
-	}()
+	a.mu.Lock()
+	a.agentData = agents
+	a.mu.Unlock()
 
-	// Fetch convoys
-	go func() {
-		convoys, err := a.adapter.ListConvoys(a.ctx, adapter.ConvoyListOpts{})
-		if err != nil {
-			a.mu.Lock()
-			a.lastError = "convoys: " + err.Error()
-			a.mu.Unlock()
-			return // Use cached data
+	a.app.QueueUpdateDraw(func() {
+		a.agents.Update(agents)
+		a.updateStatusBar()
+	})
+}
+
+// townStatusLoop polls gt status on its own, slower cadence.
+func (a *App) townStatusLoop() {
+	a.refreshTownStatus()
+
+	interval := a.config.TownStatusInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			a.refreshTownStatus()
 		}
-		a.stuck.CheckConvoys(convoys, nil)
-		a.mu.Lock()
-		a.convoyData = convoys
-		a.mu.Unlock()
-		a.app.QueueUpdateDraw(func() {
-			a.convoys.Update(convoys)
-		})
-	}()
+	}
+}
 
-	// Fetch events (direct file read, very fast)
-	go func() {
-		events, err := a.adapter.TailEvents(a.ctx, a.config.LogLines)
-		if err != nil {
-			return // Events are optional
+// refreshMergeQueue rebuilds the merge queues from the event history and
+// the refinery's queue listing. Only runs while the panel is visible.
+func (a *App) refreshMergeQueue() {
+	a.mu.RLock()
+	show := a.showRefinery
+	events := a.eventData
+	a.mu.RUnlock()
+	rig := a.engine.Rig()
+	if !show {
+		return
+	}
+
+	// gt mq may be unavailable; events alone still give a useful view
+	listed, _ := a.adapter.ListMergeQueue(a.ctx, rig)
+	queues := refinery.Build(events, listed)
+	if rig != "" {
+		var scoped []refinery.Queue
+		for _, q := range queues {
+			if q.Rig == rig {
+				scoped = append(scoped, q)
+			}
 		}
-		a.mu.Lock()
-		a.eventData = events
-		a.mu.Unlock()
-		a.app.QueueUpdateDraw(func() {
-			a.events.Update(events)
-		})
-	}()
+		queues = scoped
+	}
 
-	// Skip gt status (too slow ~4s) - status bar updates from refresh tick above
-	// Town name comes from config or is detected on startup
+	a.mu.Lock()
+	a.mergeQueues = queues
+	a.mu.Unlock()
+	a.app.QueueUpdateDraw(func() {
+		a.refinery.Update(queues)
+		a.updateStatusBar()
+	})
 }
 
 // updateStatusBar updates the status bar with current state.
 func (a *App) updateStatusBar() {
+	status := a.engine.TownStatus()
+	rigName := a.engine.Rig()
+
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
 	townName := "Gas Town"
-	if a.townStatus != nil && a.townStatus.Name != "" {
-		townName = a.townStatus.Name
+	if status != nil && status.Name != "" {
+		townName = status.Name
 	}
 
 	interval := a.config.RefreshInterval.String()
@@ -702,7 +1188,30 @@ func (a *App) updateStatusBar() {
 	// Connected = we have some data
 	connected := len(a.polecatData) > 0 || len(a.beadData) > 0 || a.lastError == ""
 
-	a.statusBar.Update(townName, a.currentRig, interval, connected, false, a.lastError)
+	var alerts []string
+	if down := a.agents.DownCount(); down > 0 {
+		alerts = append(alerts, fmt.Sprintf("%d agent(s) down", down))
+	}
+	if failing := a.refinery.AlertCount(); failing > 0 {
+		alerts = append(alerts, fmt.Sprintf("%d merge(s) failing repeatedly", failing))
+	}
+	// Snoozed items are left out
+	if s := a.stuck.Summarize(a.beadData, a.polecatData, a.convoyData); s.Total() > 0 {
+		alert := fmt.Sprintf("%d stuck", s.Total())
+		if s.Critical > 0 {
+			alert += fmt.Sprintf(" (%d critical)", s.Critical)
+		}
+		alerts = append(alerts, alert)
+	}
+	a.statusBar.SetAlert(strings.Join(alerts, ", "))
+
+	if rigName != "" && status != nil {
+		if rig := status.FindRig(rigName); rig != nil && rig.State != "" {
+			rigName += " (" + rig.State + ")"
+		}
+	}
+
+	a.statusBar.Update(townName, rigName, interval, connected, false, a.lastError)
 }
 
 // Run starts the application.
@@ -710,8 +1219,9 @@ func (a *App) Run() error {
 	// Initial refresh
 	go a.refresh()
 
-	// Start refresh loop
+	// Start refresh loops
 	go a.refreshLoop()
+	go a.townStatusLoop()
 
 	// Run the app
 	return a.app.Run()
@@ -0,0 +1,288 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/davidsenack/gastop/internal/graph"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/rivo/tview"
+)
+
+// maxDetailEvents is how many events mentioning a bead are listed.
+const maxDetailEvents = 10
+
+// BeadDetail displays detailed info for a single bead. Related beads
+// (blockers, dependents, parent, children) are links: Tab selects one.
+type BeadDetail struct {
+	view  *tview.TextView
+	id    string   // Bead shown
+	links []string // Linked bead IDs, in order of appearance
+	link  int      // Selected link, -1 for none
+}
+
+// NewBeadDetail creates a new bead detail view.
+func NewBeadDetail() *BeadDetail {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	return &BeadDetail{view: view, link: -1}
+}
+
+// Primitive returns the tview primitive.
+func (d *BeadDetail) Primitive() tview.Primitive {
+	return d.view
+}
+
+// ID returns the ID of the bead shown.
+func (d *BeadDetail) ID() string {
+	return d.id
+}
+
+// Update renders the bead, its place in the dependency graph, the polecats
+// working on it and the events that mention it (oldest first).
+func (d *BeadDetail) Update(b *model.Bead, g *graph.Graph, polecats []model.Polecat, events []model.Event) {
+	tags := GetTags()
+	d.view.SetTitle(" " + b.ID + " ")
+	if b.ID != d.id {
+		d.link = -1
+	}
+	d.id = b.ID
+	d.links = d.links[:0]
+
+	var sb strings.Builder
+	label := func(name, value string) {
+		fmt.Fprintf(&sb, "[%s]%-12s[-] %s\n", tags.Muted, name, value)
+	}
+
+	fmt.Fprintf(&sb, "[::b]%s[::-]\n\n", tview.Escape(b.Title))
+	label("Status", b.StatusIcon()+" "+string(b.Status))
+	label("Priority", b.PriorityString())
+	if b.IssueType != "" {
+		label("Type", string(b.IssueType))
+	}
+	if b.Stuck {
+		label("Stuck", stuckText(b.StuckReason, b.StuckLevel, b.StuckSince, b.Snoozed))
+	}
+	if b.Owner != "" {
+		label("Owner", tview.Escape(b.Owner))
+	}
+	if b.Assignee != "" {
+		label("Assignee", tview.Escape(b.Assignee))
+	}
+	if len(b.Labels) > 0 {
+		label("Labels", tview.Escape(strings.Join(b.Labels, ", ")))
+	}
+	if hooked := d.hookedText(b.ID, polecats); hooked != "" {
+		label("Hooked to", hooked)
+	}
+
+	if !b.CreatedAt.IsZero() {
+		label("Created", detailTime(b.CreatedAt, b.Age))
+	}
+	if !b.UpdatedAt.IsZero() {
+		label("Updated", detailTime(b.UpdatedAt, b.UpdatedAgo()))
+	}
+	if b.ClosedAt != nil {
+		label("Closed", detailTime(*b.ClosedAt, b.ClosedAgo()))
+	}
+	if b.CloseReason != "" {
+		label("Close reason", tview.Escape(b.CloseReason))
+	}
+
+	if desc := strings.TrimSpace(b.Description); desc != "" {
+		fmt.Fprintf(&sb, "\n[%s::b]Description[::-][-]\n%s\n", tags.Accent1, tview.Escape(desc))
+	}
+
+	if b.Molecule != nil {
+		d.renderMolecule(&sb, b.Molecule, label)
+	}
+
+	if g != nil {
+		d.renderGraph(&sb, b, g, label)
+	}
+
+	d.renderEvents(&sb, b.ID, events)
+
+	if len(d.links) > 0 {
+		fmt.Fprintf(&sb, "\n[%s]Tab to select a link, Enter to open it, Esc to go back[-]", tags.Dim)
+	} else {
+		fmt.Fprintf(&sb, "\n[%s]Esc to go back[-]", tags.Dim)
+	}
+	d.view.SetText(sb.String())
+	d.highlight()
+}
+
+// NextLink selects the next (delta 1) or previous (delta -1) link.
+func (d *BeadDetail) NextLink(delta int) {
+	if len(d.links) == 0 {
+		return
+	}
+	d.link = (d.link + delta + len(d.links)) % len(d.links)
+	d.highlight()
+}
+
+// SelectedLink returns the ID of the selected linked bead, or "".
+func (d *BeadDetail) SelectedLink() string {
+	if d.link < 0 || d.link >= len(d.links) {
+		return ""
+	}
+	return d.links[d.link]
+}
+
+// highlight marks the selected link and scrolls it into view.
+func (d *BeadDetail) highlight() {
+	if d.link < 0 || d.link >= len(d.links) {
+		d.link = -1
+		d.view.Highlight()
+		return
+	}
+	d.view.Highlight(fmt.Sprintf("link%d", d.link))
+	d.view.ScrollToHighlight()
+}
+
+// linkTo renders a bead ID as a link and records it.
+func (d *BeadDetail) linkTo(id, text string) string {
+	region := fmt.Sprintf("link%d", len(d.links))
+	d.links = append(d.links, id)
+	return `["` + region + `"]` + text + `[""]`
+}
+
+// hookedText describes the polecats hooked to (or assigned) a bead.
+func (d *BeadDetail) hookedText(id string, polecats []model.Polecat) string {
+	var names []string
+	for i := range polecats {
+		p := &polecats[i]
+		if p.HookedBead == id || p.AssignedBead == id {
+			names = append(names, p.StateIcon()+" "+p.FullName()+" ("+string(p.State)+")")
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// detailTime formats a timestamp with how long ago it was.
+func detailTime(t time.Time, ago string) string {
+	s := t.Local().Format("2006-01-02 15:04")
+	if ago != "" {
+		s += " [" + GetTags().Dim + "](" + ago + " ago)[-]"
+	}
+	return s
+}
+
+// renderEvents writes the most recent events that mention a bead.
+func (d *BeadDetail) renderEvents(sb *strings.Builder, id string, events []model.Event) {
+	tags := GetTags()
+
+	var related []*model.Event
+	for i := len(events) - 1; i >= 0 && len(related) < maxDetailEvents; i-- {
+		if events[i].Mentions(id) {
+			related = append(related, &events[i])
+		}
+	}
+	if len(related) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n[%s::b]Events[::-][-]\n", tags.Accent1)
+	for i := len(related) - 1; i >= 0; i-- {
+		e := related[i]
+		fmt.Fprintf(sb, "[%s]%s %s[-] %s %s\n", tags.Dim, e.Timestamp.Local().Format("01-02"), e.TimeString(),
+			e.Icon(), tview.Escape(strings.TrimSpace(e.Actor+" "+e.Summary())))
+	}
+}
+
+// renderMolecule writes the workflow steps of a molecule.
+func (d *BeadDetail) renderMolecule(sb *strings.Builder, m *model.Molecule, label func(name, value string)) {
+	tags := GetTags()
+
+	fmt.Fprintf(sb, "\n[%s::b]Workflow[::-][-]\n", tags.Accent1)
+	label("Progress", fmt.Sprintf("%s %d/%d", renderProgressBar(m.Progress(), 10), m.Done(), len(m.Steps)))
+	if m.Holder != "" {
+		label("Held by", m.Holder)
+	}
+	if m.TypicalStepDuration() > 0 {
+		label("Usual step", m.TypicalStepString())
+	}
+
+	for i, step := range m.Timeline(time.Now()) {
+		marker, color := " ", tags.Muted
+		switch {
+		case step.Current:
+			marker, color = "▶", tags.InProgress
+		case step.Status.IsDone():
+			color = tags.Done
+		}
+		fmt.Fprintf(sb, " [%s]%s %2d. %s %-10s[-] %-6s %s\n",
+			color, marker, i+1, step.StatusIcon(), step.ID, step.DurationString(), tview.Escape(step.Title))
+	}
+}
+
+// renderGraph writes the dependency section for a bead.
+func (d *BeadDetail) renderGraph(sb *strings.Builder, b *model.Bead, g *graph.Graph, label func(name, value string)) {
+	tags := GetTags()
+	id := b.ID
+	node := g.Node(id)
+	if node == nil {
+		return
+	}
+
+	fmt.Fprintf(sb, "\n[%s::b]Dependencies[::-][-]\n", tags.Accent1)
+	label("Blocked by", d.formatRefs(node.Upstream, g))
+	label("Blocks", d.formatRefs(node.Downstream, g))
+	if node.Parent != "" {
+		label("Parent", d.formatRefs([]string{node.Parent}, g))
+	}
+	if len(node.Children) > 0 {
+		label("Children", d.formatRefs(node.Children, g))
+	}
+
+	for _, cycle := range g.Cycles() {
+		for _, member := range cycle {
+			if member == id {
+				label("Cycle", "["+tags.Error+"]"+strings.Join(cycle, " ↔ ")+"[-]")
+			}
+		}
+	}
+
+	if downstream := g.Downstream(id); len(downstream) > 0 {
+		label("Unblocks", fmt.Sprintf("%d beads downstream", len(downstream)))
+	}
+	for rank, rb := range g.RootBlockers(0) {
+		if rb.ID == id {
+			label("Root blocker", fmt.Sprintf("[%s]#%d in town[-]", tags.Warning, rank+1))
+			break
+		}
+	}
+
+	if path := g.CriticalPath(id); len(path) > 1 {
+		label("Critical path", strings.Join(path, " → "))
+	}
+}
+
+// formatRefs renders bead IDs as links with their status icons.
+func (d *BeadDetail) formatRefs(ids []string, g *graph.Graph) string {
+	tags := GetTags()
+	if len(ids) == 0 {
+		return "[" + tags.Dim + "]none[-]"
+	}
+	refs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		n := g.Node(id)
+		if n == nil || n.Missing {
+			refs = append(refs, d.linkTo(id, "? "+id))
+			continue
+		}
+		refs = append(refs, d.linkTo(id, n.Bead.StatusIcon()+" "+id))
+	}
+	return strings.Join(refs, ", ")
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/model"
+)
+
+// beadMode selects the queue the beads panel shows.
+type beadMode int
+
+const (
+	beadModeAll     beadMode = iota // bd list
+	beadModeReady                   // bd ready: unblocked work
+	beadModeBlocked                 // bd blocked, with each bead's blockers
+	beadModeCount
+)
+
+// String returns the mode's name as shown in the panel title.
+func (m beadMode) String() string {
+	switch m {
+	case beadModeReady:
+		return "ready"
+	case beadModeBlocked:
+		return "blocked"
+	}
+	return "all"
+}
+
+// beadsTitle returns the beads panel title for a mode and status filter.
+func beadsTitle(mode beadMode, filter string) string {
+	var tags []string
+	if mode != beadModeAll {
+		tags = append(tags, mode.String())
+	}
+	if filter != "" {
+		tags = append(tags, filter)
+	}
+	if len(tags) == 0 {
+		return "BEADS"
+	}
+	return "BEADS [" + strings.Join(tags, ", ") + "]"
+}
+
+// modeBeads returns the beads of the current mode: the bead list, or the
+// last fetched ready or blocked queue. The caller holds a.mu.
+func (a *App) modeBeads() []model.Bead {
+	if a.beadMode == beadModeAll {
+		return a.beadData
+	}
+	return a.queueData
+}
+
+// cycleBeadMode switches the beads panel between all, ready and blocked
+// beads.
+func (a *App) cycleBeadMode() {
+	a.clearConvoyFocus()
+
+	a.mu.Lock()
+	a.beadMode = (a.beadMode + 1) % beadModeCount
+	a.queueData = nil
+	mode := a.beadMode
+	filter := a.beadStatusFilter
+	beads := a.beadData
+	a.mu.Unlock()
+
+	a.beads.SetShowBlockers(mode == beadModeBlocked)
+	a.beads.SetTitle(beadsTitle(mode, filter))
+	if mode == beadModeAll {
+		a.beads.Update(a.filterBeadsByStatus(beads, filter))
+		return
+	}
+	a.beads.Update(nil) // Until the queue arrives
+	go a.refreshBeadQueue()
+}
+
+// refreshBeadQueue fetches the ready or blocked queue for the current mode
+// and shows it in the beads panel.
+func (a *App) refreshBeadQueue() {
+	a.mu.RLock()
+	mode := a.beadMode
+	a.mu.RUnlock()
+
+	var beads []model.Bead
+	var err error
+	switch mode {
+	case beadModeReady:
+		beads, err = a.engine.ReadyBeads(a.ctx)
+	case beadModeBlocked:
+		beads, err = a.engine.BlockedBeads(a.ctx)
+	default:
+		return
+	}
+	if err != nil {
+		a.setError(mode.String() + " beads: " + err.Error())
+		return
+	}
+
+	a.mu.Lock()
+	if a.beadMode != mode {
+		a.mu.Unlock()
+		return // Switched modes meanwhile
+	}
+	a.queueData = beads
+	filter := a.beadStatusFilter
+	a.mu.Unlock()
+
+	filtered := a.filterBeadsByStatus(beads, filter)
+	a.app.QueueUpdateDraw(func() {
+		if a.inConvoyFocus() {
+			return
+		}
+		a.beads.Update(filtered)
+		a.beads.SetTitle(beadsTitle(mode, filter))
+	})
+}
@@ -14,7 +14,20 @@ type BeadsPanel struct {
 	table        *tview.Table
 	beads        []model.Bead
 	allBeads     []model.Bead // Original unfiltered data
+	shown        []model.Bead // Filtered beads before tree flattening
 	selectedFunc func(*model.Bead)
+	title        string
+
+	// Tree mode groups children under their epic/molecule parents
+	treeMode  bool
+	nodes     []*model.BeadNode // Visible nodes, parallel to beads
+	collapsed map[string]bool
+
+	// showClosed replaces the Age column with completion times
+	showClosed bool
+
+	// showBlockers lists each bead's blockers after its title
+	showBlockers bool
 }
 
 // NewBeadsPanel creates a new beads panel.
@@ -43,7 +56,7 @@ func NewBeadsPanel() *BeadsPanel {
 		table.SetCell(0, i, cell)
 	}
 
-	return &BeadsPanel{table: table}
+	return &BeadsPanel{table: table, title: "BEADS", collapsed: make(map[string]bool)}
 }
 
 // Primitive returns the tview primitive.
@@ -78,15 +91,62 @@ func (p *BeadsPanel) Selected() *model.Bead {
 
 // SetTitle sets the panel title.
 func (p *BeadsPanel) SetTitle(title string) {
+	p.title = title
+	if p.treeMode {
+		title += " [tree]"
+	}
 	p.table.SetTitle(" " + title + " ")
 }
 
+// SetShowClosed switches the last column between bead age and when the
+// bead was closed.
+func (p *BeadsPanel) SetShowClosed(show bool) {
+	p.showClosed = show
+	header := "Age"
+	if show {
+		header = "Closed"
+	}
+	p.table.GetCell(0, 5).SetText(header)
+}
+
+// SetShowBlockers switches listing each bead's blockers and their
+// statuses after its title.
+func (p *BeadsPanel) SetShowBlockers(show bool) {
+	p.showBlockers = show
+}
+
+// ToggleTree switches between the flat table and the parent/child tree.
+func (p *BeadsPanel) ToggleTree() {
+	p.treeMode = !p.treeMode
+	p.SetTitle(p.title)
+	p.updateDisplay(p.shown)
+}
+
+// ToggleCollapse collapses or expands the selected parent in tree mode.
+func (p *BeadsPanel) ToggleCollapse() {
+	row, _ := p.table.GetSelection()
+	if !p.treeMode || row < 1 || row-1 >= len(p.nodes) {
+		return
+	}
+	n := p.nodes[row-1]
+	if !n.HasChildren() {
+		return
+	}
+	id := n.Bead.ID
+	if p.collapsed[id] {
+		delete(p.collapsed, id)
+	} else {
+		p.collapsed[id] = true
+	}
+	p.updateDisplay(p.shown)
+}
+
 // FilterByStatus filters the displayed beads by status.
 func (p *BeadsPanel) FilterByStatus(status string) {
 	// Store original beads and filter
 	var filtered []model.Bead
 	for _, b := range p.allBeads {
-		if status == "" || b.Status == status {
+		if status == "" || b.Status.Is(model.BeadStatus(status)) {
 			filtered = append(filtered, b)
 		}
 	}
@@ -98,11 +158,12 @@ func (p *BeadsPanel) FilterByStatus(status string) {
 	}
 }
 
-// Search filters beads by ID or Title (case-insensitive).
-func (p *BeadsPanel) Search(query string) {
+// Search filters beads by ID or Title (case-insensitive). The search tag
+// is appended to title, the panel's current mode and filter title.
+func (p *BeadsPanel) Search(query, title string) {
 	if query == "" {
 		p.updateDisplay(p.allBeads)
-		p.SetTitle("BEADS")
+		p.SetTitle(title)
 		return
 	}
 
@@ -115,18 +176,26 @@ func (p *BeadsPanel) Search(query string) {
 		}
 	}
 	p.updateDisplay(filtered)
-	p.SetTitle(fmt.Sprintf("BEADS [search: %s]", query))
+	p.SetTitle(fmt.Sprintf("%s [search: %s]", title, query))
 }
 
-// ClearSearch resets the beads display to show all beads.
-func (p *BeadsPanel) ClearSearch() {
+// ClearSearch resets the beads display to show all beads under title.
+func (p *BeadsPanel) ClearSearch(title string) {
 	p.updateDisplay(p.allBeads)
-	p.SetTitle("BEADS")
+	p.SetTitle(title)
 }
 
 // updateDisplay updates the table without changing allBeads.
 func (p *BeadsPanel) updateDisplay(beads []model.Bead) {
-	theme := GetTheme()
+	p.shown = beads
+	p.nodes = nil
+	if p.treeMode {
+		p.nodes = model.Flatten(model.BuildBeadTree(beads), p.collapsed)
+		beads = make([]model.Bead, len(p.nodes))
+		for i, n := range p.nodes {
+			beads[i] = n.Bead
+		}
+	}
 	p.beads = beads
 
 	// Remember current selection
@@ -138,64 +207,134 @@ func (p *BeadsPanel) updateDisplay(beads []model.Bead) {
 	}
 
 	// Add bead rows
-	for i, b := range beads {
-		row := i + 1 // Skip header
-
-		// Status icon
-		icon := b.StatusIcon()
-		iconColor := theme.Foreground
-		if b.Stuck {
-			icon = "⚠"
-			iconColor = theme.Stuck
-		} else {
-			switch b.Status {
-			case "in_progress":
-				iconColor = theme.InProgress
-			case "closed":
-				iconColor = theme.Done
-			case "blocked":
-				iconColor = theme.Blocked
-			case "deferred":
-				iconColor = theme.Warning
-			case "open":
-				iconColor = theme.Info
-			}
+	for i := range beads {
+		var node *model.BeadNode
+		if p.nodes != nil {
+			node = p.nodes[i]
 		}
+		p.setRow(i+1, &beads[i], node) // Skip header
+	}
 
-		p.table.SetCell(row, 0, tview.NewTableCell(icon).SetTextColor(iconColor))
-		p.table.SetCell(row, 1, tview.NewTableCell(b.ID).SetTextColor(theme.Accent1))
-		p.table.SetCell(row, 2, tview.NewTableCell(b.Status).SetTextColor(theme.Muted))
-
-		// Priority with color coding
-		priCell := tview.NewTableCell(b.PriorityString())
-		switch b.Priority {
-		case 0:
-			priCell.SetTextColor(theme.Error) // P0 = critical
-		case 1:
-			priCell.SetTextColor(theme.Warning) // P1 = high
-		default:
-			priCell.SetTextColor(theme.Muted)
+	// Restore selection
+	if currentRow > 0 && currentRow <= len(beads) {
+		p.table.Select(currentRow, 0)
+	} else if len(beads) > 0 {
+		p.table.Select(1, 0)
+	}
+}
+
+// setRow renders a bead into a table row. In tree mode node carries the
+// indentation and rolled-up progress and stuck status.
+func (p *BeadsPanel) setRow(row int, b *model.Bead, node *model.BeadNode) {
+	theme := GetTheme()
+
+	// Status icon
+	icon := b.StatusIcon()
+	iconColor := theme.Foreground
+	if b.Stuck {
+		icon = "⚠"
+		iconColor = theme.StuckColor(b.StuckLevel, b.Snoozed)
+	} else if node != nil && node.AnyStuck() {
+		icon = "⚠"
+		iconColor = theme.Warning
+	} else {
+		switch b.Status.Canonical() {
+		case model.StatusInProgress, model.StatusHooked:
+			iconColor = theme.InProgress
+		case model.StatusClosed, model.StatusTombstone:
+			iconColor = theme.Done
+		case model.StatusBlocked:
+			iconColor = theme.Blocked
+		case model.StatusDeferred:
+			iconColor = theme.Warning
+		case model.StatusOpen:
+			iconColor = theme.Info
 		}
-		p.table.SetCell(row, 3, priCell)
+	}
 
-		// Truncate title if needed
-		title := b.Title
-		if len(title) > 40 {
-			title = title[:37] + "..."
+	p.table.SetCell(row, 0, tview.NewTableCell(icon).SetTextColor(iconColor))
+	p.table.SetCell(row, 1, tview.NewTableCell(b.ID).SetTextColor(theme.Accent1))
+	p.table.SetCell(row, 2, tview.NewTableCell(string(b.Status)).SetTextColor(theme.Muted))
+
+	// Priority with color coding
+	priCell := tview.NewTableCell(b.PriorityString())
+	switch b.Priority {
+	case 0:
+		priCell.SetTextColor(theme.Error) // P0 = critical
+	case 1:
+		priCell.SetTextColor(theme.Warning) // P1 = high
+	default:
+		priCell.SetTextColor(theme.Muted)
+	}
+	p.table.SetCell(row, 3, priCell)
+
+	// Truncate title if needed
+	title := b.Title
+	if len(title) > 40 {
+		title = title[:37] + "..."
+	}
+	if m := b.Molecule; m != nil && len(m.Steps) > 0 {
+		title += fmt.Sprintf(" %s %d/%d", renderProgressBar(m.Progress(), 6), m.Done(), len(m.Steps))
+	}
+	if node != nil {
+		title = treePrefix(node, p.collapsed[b.ID]) + title
+		if node.HasChildren() {
+			title += fmt.Sprintf(" (%s", node.Progress())
+			if node.Stuck > 0 {
+				title += fmt.Sprintf(", %d stuck", node.Stuck)
+			}
+			title += ")"
 		}
-		titleCell := tview.NewTableCell(title).SetExpansion(1).SetTextColor(theme.Foreground)
-		if b.Stuck {
-			titleCell.SetTextColor(theme.Stuck)
+	}
+	if p.showBlockers && len(b.Blockers) > 0 {
+		title += "  ← " + blockersText(b.Blockers)
+	}
+	titleCell := tview.NewTableCell(title).SetExpansion(1).SetTextColor(theme.Foreground)
+	if b.Stuck {
+		titleCell.SetTextColor(theme.StuckColor(b.StuckLevel, b.Snoozed))
+	}
+	p.table.SetCell(row, 4, titleCell)
+
+	age := b.Age
+	if p.showClosed {
+		age = "-"
+		if b.ClosedAt != nil {
+			age = b.ClosedAgo() + " ago"
 		}
-		p.table.SetCell(row, 4, titleCell)
+	}
+	p.table.SetCell(row, 5, tview.NewTableCell(age).SetTextColor(theme.Muted))
+}
+
+// maxBlockersShown is how many blockers are listed after a title.
+const maxBlockersShown = 3
 
-		p.table.SetCell(row, 5, tview.NewTableCell(b.Age).SetTextColor(theme.Muted))
+// blockersText lists blockers with their statuses, e.g.
+// "gt-1 in_progress, gt-2 open".
+func blockersText(blockers []model.Blocker) string {
+	parts := make([]string, 0, maxBlockersShown+1)
+	for i, dep := range blockers {
+		if i == maxBlockersShown {
+			parts = append(parts, fmt.Sprintf("+%d more", len(blockers)-i))
+			break
+		}
+		status := string(dep.Status)
+		if status == "" {
+			status = "?"
+		}
+		parts = append(parts, dep.ID+" "+status)
 	}
+	return strings.Join(parts, ", ")
+}
 
-	// Restore selection
-	if currentRow > 0 && currentRow <= len(beads) {
-		p.table.Select(currentRow, 0)
-	} else if len(beads) > 0 {
-		p.table.Select(1, 0)
+// treePrefix returns the indentation and expand marker for a tree row.
+func treePrefix(n *model.BeadNode, collapsed bool) string {
+	prefix := strings.Repeat("  ", n.Depth)
+	switch {
+	case !n.HasChildren():
+		return prefix + "  "
+	case collapsed:
+		return prefix + "▸ "
+	default:
+		return prefix + "▾ "
 	}
 }
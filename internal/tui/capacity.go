@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/capacity"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// capacityReadyShown is how many ready beads are listed per rig.
+const capacityReadyShown = 5
+
+// CapacityView shows ready work against idle polecats per rig, with
+// numbered suggestions for slinging the top ready beads.
+type CapacityView struct {
+	view        *tview.TextView
+	suggestions []capacity.Suggestion // Numbered 1-9 in the view
+}
+
+// NewCapacityView creates a new capacity view.
+func NewCapacityView() *CapacityView {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetTitle(" Capacity ").
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	return &CapacityView{view: view}
+}
+
+// Primitive returns the tview primitive.
+func (v *CapacityView) Primitive() tview.Primitive {
+	return v.view
+}
+
+// SetLoading shows that ready work is being fetched.
+func (v *CapacityView) SetLoading() {
+	v.suggestions = nil
+	v.view.SetText("[" + GetTags().Dim + "]Loading ready work...[-]")
+}
+
+// SetError shows why ready work couldn't be fetched.
+func (v *CapacityView) SetError(err error) {
+	tags := GetTags()
+	v.suggestions = nil
+	v.view.SetText(fmt.Sprintf("[%s]bd ready failed: %s[-]\n\n[%s]r to retry, Esc to close[-]",
+		tags.Error, tview.Escape(err.Error()), tags.Dim))
+}
+
+// Suggestion returns the i-th numbered suggestion (0-based), or nil.
+func (v *CapacityView) Suggestion(i int) *capacity.Suggestion {
+	if i < 0 || i >= len(v.suggestions) {
+		return nil
+	}
+	return &v.suggestions[i]
+}
+
+// Update renders the rigs.
+func (v *CapacityView) Update(rigs []capacity.Rig) {
+	tags := GetTags()
+	v.suggestions = nil
+
+	var sb strings.Builder
+	for i := range rigs {
+		v.renderRig(&sb, &rigs[i])
+	}
+	if len(rigs) == 0 {
+		fmt.Fprintf(&sb, "[%s]No rigs or ready work[-]\n\n", tags.Muted)
+	}
+	if len(v.suggestions) > 0 {
+		fmt.Fprintf(&sb, "[%s]1-%d to sling, r to reload, Esc to close[-]", tags.Dim, len(v.suggestions))
+	} else {
+		fmt.Fprintf(&sb, "[%s]r to reload, Esc to close[-]", tags.Dim)
+	}
+	v.view.SetText(sb.String())
+	v.view.ScrollToBeginning()
+}
+
+// renderRig writes one rig: its counts, top ready beads, idle polecats and
+// sling suggestions.
+func (v *CapacityView) renderRig(sb *strings.Builder, r *capacity.Rig) {
+	tags := GetTags()
+
+	if r.Name == "" {
+		v.renderUnassigned(sb, r)
+		return
+	}
+	fmt.Fprintf(sb, "[%s::b]%s[::-][-]  %d ready · %d idle · %d working", tags.Accent1, r.Name, len(r.Ready), len(r.Idle), r.Working)
+	if r.TopWait > 0 {
+		waitTag := tags.Muted
+		if r.Shortfall() > 0 && len(r.Idle) == 0 {
+			waitTag = tags.Warning // Work waiting and no one to take it
+		}
+		fmt.Fprintf(sb, "  [%s]top waited %s[-]", waitTag, model.HumanizeDuration(r.TopWait))
+	}
+	sb.WriteString("\n")
+
+	renderReady(sb, r.Ready)
+
+	if len(r.Idle) > 0 {
+		names := make([]string, len(r.Idle))
+		for i, p := range r.Idle {
+			names[i] = p.Name
+		}
+		fmt.Fprintf(sb, "  [%s]idle:[-] %s\n", tags.Idle, strings.Join(names, ", "))
+	}
+
+	switch {
+	case len(r.Ready) == 0 && len(r.Idle) > 0:
+		fmt.Fprintf(sb, "  [%s]Nothing ready for idle polecats[-]\n", tags.Muted)
+	case r.Shortfall() > 0:
+		fmt.Fprintf(sb, "  [%s]%d ready without an idle polecat[-]\n", tags.Warning, r.Shortfall())
+	}
+
+	for _, s := range r.Suggestions() {
+		if len(v.suggestions) == 9 {
+			break
+		}
+		v.suggestions = append(v.suggestions, s)
+		key := tview.Escape(fmt.Sprintf("[%d]", len(v.suggestions)))
+		fmt.Fprintf(sb, "  [%s::b]%s[::-][-] sling %s → %s\n", tags.Accent2, key, s.Bead.ID, s.Polecat.FullName())
+	}
+	sb.WriteString("\n")
+}
+
+// renderUnassigned writes the ready beads whose rig couldn't be resolved.
+// No polecat can be suggested for them, so they are listed to sling by hand.
+func (v *CapacityView) renderUnassigned(sb *strings.Builder, r *capacity.Rig) {
+	tags := GetTags()
+	if len(r.Ready) == 0 {
+		return // Only polecats without a rig; nothing to match
+	}
+	fmt.Fprintf(sb, "[%s::b]unassigned[::-][-]  %d ready\n", tags.Warning, len(r.Ready))
+	fmt.Fprintf(sb, "  [%s]Rig unknown, so no polecat can be suggested; sling from the beads panel[-]\n", tags.Muted)
+	renderReady(sb, r.Ready)
+	sb.WriteString("\n")
+}
+
+// renderReady lists the first ready beads of a rig.
+func renderReady(sb *strings.Builder, ready []model.Bead) {
+	tags := GetTags()
+	for i, b := range ready {
+		if i == capacityReadyShown {
+			fmt.Fprintf(sb, "  [%s]+%d more[-]\n", tags.Dim, len(ready)-i)
+			break
+		}
+		fmt.Fprintf(sb, "  %s %-10s %s [%s]%s[-]\n", b.PriorityString(), b.ID,
+			tview.Escape(truncate(b.Title, 48)), tags.Dim, b.Age)
+	}
+}
+
+// showCapacityView opens the full-screen capacity view and fetches ready
+// work in the background.
+func (a *App) showCapacityView() {
+	view := NewCapacityView()
+
+	load := func() {
+		view.SetLoading()
+		go func() {
+			rigs, err := a.engine.Capacity(a.ctx)
+			a.app.QueueUpdateDraw(func() {
+				if err != nil {
+					view.SetError(err)
+					return
+				}
+				view.Update(rigs)
+			})
+		}()
+	}
+
+	view.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeOverlay()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch r := event.Rune(); {
+		case r == 'r':
+			load()
+			return nil
+		case r >= '1' && r <= '9':
+			if s := view.Suggestion(int(r - '1')); s != nil {
+				a.confirmCapacitySling(view, *s)
+			}
+			return nil
+		}
+		return event
+	})
+
+	a.app.SetRoot(view.Primitive(), true)
+	a.app.SetFocus(view.Primitive())
+	load()
+}
+
+// confirmCapacitySling asks before slinging a suggestion, returning to the
+// capacity view on cancel.
+func (a *App) confirmCapacitySling(view *CapacityView, s capacity.Suggestion) {
+	modal := tview.NewModal().
+		SetText("Sling " + s.Bead.ID + " to " + s.Polecat.FullName() + "?\n\n" + s.Bead.Title).
+		AddButtons([]string{"Cancel", "Sling"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonLabel == "Sling" {
+				a.closeOverlay()
+				a.slingToPolecat(s.Bead.ID, s.Polecat)
+				return
+			}
+			a.app.SetRoot(view.Primitive(), true)
+			a.app.SetFocus(view.Primitive())
+		})
+	a.app.SetRoot(modal, true)
+}
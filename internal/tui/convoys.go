@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/davidsenack/gastop/internal/model"
 	"github.com/rivo/tview"
@@ -49,8 +50,8 @@ func (p *ConvoysPanel) Update(convoys []model.Convoy) {
 		// Build primary text with status icon
 		var icon string
 		if c.Stuck {
-			icon = "[" + tags.Error + "]⚠[-]"
-		} else if c.Status == "closed" {
+			icon = "[" + tags.StuckTag(c.StuckLevel, c.Snoozed) + "]⚠[-]"
+		} else if c.Status.Is(model.StatusClosed) {
 			icon = "[" + tags.Done + "]✓[-]"
 		} else {
 			icon = "[" + tags.Accent1 + "]●[-]"
@@ -63,14 +64,27 @@ func (p *ConvoysPanel) Update(convoys []model.Convoy) {
 
 		// Build secondary text with progress
 		secondary := fmt.Sprintf("  [%s]%s[-] ", tags.Dim, c.ID)
-		if c.TotalCount > 0 {
+		if c.Counts != nil {
+			// Show per-status breakdown
+			secondary += renderSegmentedBar(*c.Counts, 8) + " "
+		} else if c.TotalCount > 0 {
 			// Show progress bar
 			pct := float64(c.ClosedCount) / float64(c.TotalCount)
 			secondary += renderProgressBar(pct, 8) + " "
 		}
 		secondary += fmt.Sprintf("[%s]%d/%d[-]", tags.Muted, c.ClosedCount, c.TotalCount)
 		if c.Stuck {
-			secondary += " [" + tags.Error + "]STUCK[-]"
+			secondary += " [" + tags.StuckTag(c.StuckLevel, c.Snoozed) + "]STUCK[-]"
+			// Name the tracked bead that caused it
+			if len(c.StuckBeads) > 0 {
+				secondary += " [" + tags.Accent1 + "]" + c.StuckBeads[0] + "[-]"
+				if n := len(c.StuckBeads) - 1; n > 0 {
+					secondary += fmt.Sprintf(" [%s]+%d[-]", tags.Dim, n)
+				}
+			}
+			if d := model.StuckFor(c.StuckSince); d != "" {
+				secondary += " [" + tags.Dim + "]" + d + "[-]"
+			}
 		}
 
 		idx := i
@@ -107,6 +121,42 @@ func renderProgressBar(pct float64, width int) string {
 	return bar
 }
 
+// renderSegmentedBar renders a bar split by bead status: closed, in
+// progress, blocked, open, then unresolved.
+func renderSegmentedBar(counts model.ConvoyCounts, width int) string {
+	tags := GetTags()
+	total := counts.Total()
+	if total == 0 {
+		return renderProgressBar(0, width)
+	}
+
+	segments := []struct {
+		n     int
+		color string
+		char  string
+	}{
+		{counts.Closed, tags.Done, "█"},
+		{counts.InProgress, tags.InProgress, "█"},
+		{counts.Blocked, tags.Blocked, "█"},
+		{counts.Open, tags.Info, "░"},
+		{counts.Unknown, tags.Dim, "░"},
+	}
+
+	// Round cumulative counts so segments always fill the width exactly
+	bar := "["
+	cum, drawn := 0, 0
+	for _, seg := range segments {
+		cum += seg.n
+		end := (cum*width + total/2) / total
+		if end > drawn {
+			bar += "[" + seg.color + "]" + strings.Repeat(seg.char, end-drawn) + "[-]"
+			drawn = end
+		}
+	}
+	bar += "]"
+	return bar
+}
+
 // SetSelectedFunc sets the callback for when a convoy is selected.
 func (p *ConvoysPanel) SetSelectedFunc(fn func(*model.Convoy)) {
 	p.selectedFunc = fn
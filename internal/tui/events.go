@@ -2,17 +2,24 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/davidsenack/gastop/internal/model"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-// EventsPanel displays the event log.
+// EventsPanel displays the event log. j/k select an event and Enter
+// expands it to show its full payload.
 type EventsPanel struct {
 	view     *tview.TextView
 	maxLines int
 	events   []model.Event
+
+	// selected is the index of the highlighted event, or -1 to follow the
+	// newest. Expanded events are keyed so they survive refreshes.
+	selected int
+	expanded map[string]bool
 }
 
 // NewEventsPanel creates a new events panel.
@@ -20,6 +27,7 @@ func NewEventsPanel(maxLines int) *EventsPanel {
 	theme := GetTheme()
 	view := tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true).
 		SetWrap(false).
 		SetTextColor(theme.Foreground)
@@ -29,10 +37,20 @@ func NewEventsPanel(maxLines int) *EventsPanel {
 		SetBorderColor(theme.BorderColor).
 		SetTitleColor(theme.TitleColor)
 
-	return &EventsPanel{
+	p := &EventsPanel{
 		view:     view,
 		maxLines: maxLines,
+		selected: -1,
+		expanded: make(map[string]bool),
 	}
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEnter {
+			p.ToggleExpanded()
+			return nil
+		}
+		return event
+	})
+	return p
 }
 
 // Primitive returns the tview primitive.
@@ -42,26 +60,77 @@ func (p *EventsPanel) Primitive() tview.Primitive {
 
 // Update updates the panel with new event data.
 func (p *EventsPanel) Update(events []model.Event) {
-	tags := GetTags()
+	// Keep the selection on the same event as the window slides
+	var selectedKey string
+	if p.selected >= 0 && p.selected < len(p.events) {
+		selectedKey = eventKey(&p.events[p.selected])
+	}
 	p.events = events
+	p.selected = -1
+	for i := range events {
+		if selectedKey != "" && eventKey(&events[i]) == selectedKey {
+			p.selected = i
+		}
+	}
+	p.render()
+}
+
+// render redraws the event lines, with payloads under expanded events.
+func (p *EventsPanel) render() {
+	tags := GetTags()
 
 	var text string
-	for _, e := range events {
+	for i, e := range p.events {
 		// Format: timestamp icon summary
 		line := fmt.Sprintf("[%s]%s[-] %s %s",
 			tags.Dim,
 			e.TimeString(),
 			p.colorIcon(e.Icon(), e.Type),
-			e.Summary(),
+			tview.Escape(e.Summary()),
 		)
 		if e.Actor != "" {
 			line += fmt.Sprintf(" [%s](%s)[-]", tags.Muted, e.Actor)
 		}
-		text += line + "\n"
+		text += fmt.Sprintf(`["ev%d"]%s[""]`, i, line) + "\n"
+
+		if p.expanded[eventKey(&e)] {
+			payload := model.FormatPayload(e.Payload)
+			if len(payload) == 0 {
+				payload = []string{"(no payload)"}
+			}
+			for _, pl := range payload {
+				text += fmt.Sprintf("         [%s]%s[-]\n", tags.Dim, tview.Escape(pl))
+			}
+		}
 	}
 
 	p.view.SetText(text)
-	p.view.ScrollToEnd()
+	if p.selected >= 0 {
+		p.view.Highlight(fmt.Sprintf("ev%d", p.selected))
+		p.view.ScrollToHighlight()
+	} else {
+		p.view.Highlight()
+		p.view.ScrollToEnd()
+	}
+}
+
+// ToggleExpanded shows or hides the payload of the selected event.
+func (p *EventsPanel) ToggleExpanded() {
+	if p.selected < 0 || p.selected >= len(p.events) {
+		return
+	}
+	key := eventKey(&p.events[p.selected])
+	if p.expanded[key] {
+		delete(p.expanded, key)
+	} else {
+		p.expanded[key] = true
+	}
+	p.render()
+}
+
+// eventKey identifies an event across refreshes.
+func eventKey(e *model.Event) string {
+	return e.Timestamp.Format(time.RFC3339Nano) + "|" + e.Type + "|" + e.Actor
 }
 
 // colorIcon returns the icon with appropriate color.
@@ -96,11 +165,11 @@ func (p *EventsPanel) SetTitle(title string) {
 
 // AppendEvent adds a new event to the display.
 func (p *EventsPanel) AppendEvent(e model.Event) {
-	p.events = append(p.events, e)
-	if len(p.events) > p.maxLines {
-		p.events = p.events[1:]
+	events := append(append([]model.Event(nil), p.events...), e)
+	if len(events) > p.maxLines {
+		events = events[1:]
 	}
-	p.Update(p.events)
+	p.Update(events)
 }
 
 // SetBackgroundColor sets the background color.
@@ -108,26 +177,43 @@ func (p *EventsPanel) SetBackgroundColor(color tcell.Color) {
 	p.view.SetBackgroundColor(color)
 }
 
-// ScrollDown scrolls the view down one line.
+// ScrollDown selects the next event. Moving past the newest event goes
+// back to following new events.
 func (p *EventsPanel) ScrollDown() {
-	row, col := p.view.GetScrollOffset()
-	p.view.ScrollTo(row+1, col)
+	if p.selected < 0 {
+		return
+	}
+	p.selected++
+	if p.selected >= len(p.events) {
+		p.selected = -1
+	}
+	p.render()
 }
 
-// ScrollUp scrolls the view up one line.
+// ScrollUp selects the previous event, starting from the newest.
 func (p *EventsPanel) ScrollUp() {
-	row, col := p.view.GetScrollOffset()
-	if row > 0 {
-		p.view.ScrollTo(row-1, col)
+	switch {
+	case len(p.events) == 0:
+		return
+	case p.selected < 0:
+		p.selected = len(p.events) - 1
+	case p.selected > 0:
+		p.selected--
 	}
+	p.render()
 }
 
-// ScrollToTop scrolls to the top of the view.
+// ScrollToTop selects the oldest event.
 func (p *EventsPanel) ScrollToTop() {
-	p.view.ScrollToBeginning()
+	if len(p.events) == 0 {
+		return
+	}
+	p.selected = 0
+	p.render()
 }
 
-// ScrollToBottom scrolls to the bottom of the view.
+// ScrollToBottom goes back to following the newest event.
 func (p *EventsPanel) ScrollToBottom() {
-	p.view.ScrollToEnd()
+	p.selected = -1
+	p.render()
 }
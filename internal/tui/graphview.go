@@ -0,0 +1,487 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/davidsenack/gastop/internal/graph"
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	graphBoxInner   = 20 // Visible width inside a node box
+	graphDefDepth   = 2
+	graphMaxDepth   = 5
+	graphSlotHeight = 5 // Four box lines plus a spacer
+)
+
+// GraphView renders the dependency neighborhood of a bead as a left-to-right
+// DAG: blockers on the left, dependents on the right.
+type GraphView struct {
+	view   *tview.TextView
+	graph  *graph.Graph
+	center string
+	depth  int
+
+	// Columns left to right; centerCol holds the centered bead
+	levels    [][]string
+	centerCol int
+	col, row  int
+}
+
+// NewGraphView creates a new dependency graph view.
+func NewGraphView() *GraphView {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	v := &GraphView{view: view, depth: graphDefDepth}
+	view.SetInputCapture(v.handleKey)
+	return v
+}
+
+// Primitive returns the tview primitive.
+func (v *GraphView) Primitive() tview.Primitive {
+	return v.view
+}
+
+// SetDoneFunc sets the callback for closing the view.
+func (v *GraphView) SetDoneFunc(fn func()) {
+	v.view.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape && fn != nil {
+			fn()
+		}
+	})
+}
+
+// SetGraph sets the graph and centers the view on a bead.
+func (v *GraphView) SetGraph(g *graph.Graph, center string) {
+	v.graph = g
+	v.recenter(center)
+}
+
+// Selected returns the ID of the selected bead.
+func (v *GraphView) Selected() string {
+	if v.col < len(v.levels) && v.row < len(v.levels[v.col]) {
+		return v.levels[v.col][v.row]
+	}
+	return ""
+}
+
+// recenter rebuilds the columns around a bead and selects it.
+func (v *GraphView) recenter(id string) {
+	v.center = id
+	upstream, downstream := v.graph.Neighborhood(id, v.depth)
+
+	v.levels = v.levels[:0]
+	for i := len(upstream) - 1; i >= 0; i-- {
+		v.levels = append(v.levels, upstream[i])
+	}
+	v.centerCol = len(v.levels)
+	v.levels = append(v.levels, []string{id})
+	v.levels = append(v.levels, downstream...)
+	v.orderLevels()
+
+	v.col, v.row = v.centerCol, 0
+	v.render()
+}
+
+// orderLevels sorts each column by the average row of its neighbors in the
+// column nearer the center, so edges run as straight as they can.
+func (v *GraphView) orderLevels() {
+	for c := v.centerCol + 1; c < len(v.levels); c++ {
+		v.sortLevel(c, c-1, func(n *graph.Node) []string { return n.Upstream })
+	}
+	for c := v.centerCol - 1; c >= 0; c-- {
+		v.sortLevel(c, c+1, func(n *graph.Node) []string { return n.Downstream })
+	}
+}
+
+// sortLevel orders column col by where each bead's neighbors sit in
+// column ref. Beads with no neighbor there go last.
+func (v *GraphView) sortLevel(col, ref int, neighbors func(n *graph.Node) []string) {
+	pos := make(map[string]int, len(v.levels[ref]))
+	for i, id := range v.levels[ref] {
+		pos[id] = i
+	}
+	key := make(map[string]float64, len(v.levels[col]))
+	for _, id := range v.levels[col] {
+		sum, count := 0, 0
+		if n := v.graph.Node(id); n != nil {
+			for _, nb := range neighbors(n) {
+				if p, ok := pos[nb]; ok {
+					sum += p
+					count++
+				}
+			}
+		}
+		key[id] = float64(len(v.levels[ref]))
+		if count > 0 {
+			key[id] = float64(sum) / float64(count)
+		}
+	}
+	level := v.levels[col]
+	sort.SliceStable(level, func(i, j int) bool { return key[level[i]] < key[level[j]] })
+}
+
+// handleKey implements hjkl navigation, Enter to re-center and +/- depth.
+func (v *GraphView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyLeft:
+		v.move(-1, 0)
+	case tcell.KeyRight:
+		v.move(1, 0)
+	case tcell.KeyUp:
+		v.move(0, -1)
+	case tcell.KeyDown:
+		v.move(0, 1)
+	case tcell.KeyEnter:
+		if id := v.Selected(); id != "" {
+			v.recenter(id)
+		}
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'h':
+			v.move(-1, 0)
+		case 'l':
+			v.move(1, 0)
+		case 'k':
+			v.move(0, -1)
+		case 'j':
+			v.move(0, 1)
+		case '+', '=':
+			if v.depth < graphMaxDepth {
+				v.depth++
+				v.recenter(v.center)
+			}
+		case '-':
+			if v.depth > 1 {
+				v.depth--
+				v.recenter(v.center)
+			}
+		default:
+			return event
+		}
+	default:
+		return event
+	}
+	return nil
+}
+
+// move changes the selection by columns/rows, clamping to the grid.
+func (v *GraphView) move(dcol, drow int) {
+	col := v.col + dcol
+	if col < 0 || col >= len(v.levels) {
+		return
+	}
+	row := v.row + drow
+	if row >= len(v.levels[col]) {
+		row = len(v.levels[col]) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	v.col, v.row = col, row
+	v.render()
+}
+
+// render draws the columns, node boxes and connectors.
+func (v *GraphView) render() {
+	tags := GetTags()
+	v.view.SetTitle(fmt.Sprintf(" DEPENDENCIES %s [depth %d] ", v.center, v.depth))
+
+	var b strings.Builder
+
+	rows := 0
+	for _, level := range v.levels {
+		rows = max(rows, len(level))
+	}
+	gaps := make([]edgeGap, len(v.levels))
+	for c := 0; c+1 < len(v.levels); c++ {
+		gaps[c] = v.edges(c, rows*graphSlotHeight)
+	}
+
+	// Column headers
+	for c := range v.levels {
+		var header string
+		switch {
+		case c < v.centerCol:
+			header = fmt.Sprintf("blockers -%d", v.centerCol-c)
+		case c == v.centerCol:
+			header = "selected"
+		default:
+			header = fmt.Sprintf("dependents +%d", c-v.centerCol)
+		}
+		b.WriteString("[" + tags.Muted + "]" + fitWidth(" "+header, graphBoxInner+2) + "[-]" + strings.Repeat(" ", gaps[c].width))
+	}
+	b.WriteString("\n")
+
+	for r := 0; r < rows; r++ {
+		for line := 0; line < graphSlotHeight; line++ {
+			for c, level := range v.levels {
+				if r >= len(level) {
+					b.WriteString(strings.Repeat(" ", graphBoxInner+2))
+				} else {
+					b.WriteString(v.boxLine(level[r], line, c == v.col && r == v.row, c == v.centerCol))
+				}
+				b.WriteString(gaps[c].line(r*graphSlotHeight + line))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(v.footer())
+	v.view.SetText(b.String())
+}
+
+// boxLine returns one line of a node's box.
+func (v *GraphView) boxLine(id string, line int, selected, center bool) string {
+	tags := GetTags()
+	n := v.graph.Node(id)
+	color := v.statusColor(id)
+
+	tl, tr, bl, br, hz, vt := "┌", "┐", "└", "┘", "─", "│"
+	if center {
+		tl, tr, bl, br, hz, vt = "╔", "╗", "╚", "╝", "═", "║"
+	}
+	edge := func(s string) string { return "[" + color + "]" + s + "[-]" }
+
+	switch line {
+	case 0:
+		return edge(tl + strings.Repeat(hz, graphBoxInner) + tr)
+	case 1:
+		icon := "?"
+		if n != nil && !n.Missing {
+			icon = n.Bead.StatusIcon()
+		}
+		text := tview.Escape(fitWidth(icon+" "+id, graphBoxInner))
+		if selected {
+			text = "[" + tags.Accent3 + "::r]" + text + "[-::-]"
+		}
+		return edge(vt) + text + edge(vt)
+	case 2:
+		title := ""
+		if n != nil {
+			title = n.Bead.Title
+		}
+		return edge(vt) + "[" + tags.Muted + "]" + tview.Escape(fitWidth(title, graphBoxInner)) + "[-]" + edge(vt)
+	case 3:
+		return edge(bl + strings.Repeat(hz, graphBoxInner) + br)
+	default:
+		return strings.Repeat(" ", graphBoxInner+2)
+	}
+}
+
+// Directions a connector cell joins, combined into box-drawing glyphs.
+const (
+	linkUp = 1 << iota
+	linkDown
+	linkLeft
+	linkRight
+)
+
+var linkGlyphs = map[int]string{
+	linkLeft: "─", linkRight: "─", linkUp: "│", linkDown: "│",
+	linkLeft | linkRight: "─", linkUp | linkDown: "│",
+	linkRight | linkDown: "┌", linkRight | linkUp: "└",
+	linkLeft | linkDown: "┐", linkLeft | linkUp: "┘",
+	linkLeft | linkRight | linkDown: "┬", linkLeft | linkRight | linkUp: "┴",
+	linkUp | linkDown | linkRight: "├", linkUp | linkDown | linkLeft: "┤",
+	linkUp | linkDown | linkLeft | linkRight: "┼",
+}
+
+// gapCell is one character of the connectors between two columns.
+type gapCell struct {
+	links   int
+	arrow   bool
+	blocked bool // Part of an edge from an unfinished bead
+}
+
+// edgeGap holds the connectors drawn between a column and the next.
+type edgeGap struct {
+	width int
+	cells [][]gapCell // By line, then x
+}
+
+// edges routes the edges from column col into column col+1. Each bead with
+// edges leaves its ID line into a lane of its own, which runs up or down to
+// every bead it feeds and ends in an arrow on that box's title line, so
+// edges leaving and entering a row never share a line. Edges from
+// unfinished beads are drawn in the blocked color so blocked chains stand
+// out.
+func (v *GraphView) edges(col, lines int) edgeGap {
+	rowOf := make(map[string]int, len(v.levels[col+1]))
+	for r, id := range v.levels[col+1] {
+		rowOf[id] = r
+	}
+
+	type fan struct {
+		src  int
+		dsts []int
+	}
+	var fans []fan
+	for r, id := range v.levels[col] {
+		n := v.graph.Node(id)
+		if n == nil {
+			continue
+		}
+		f := fan{src: r}
+		for _, down := range n.Downstream {
+			if t, ok := rowOf[down]; ok {
+				f.dsts = append(f.dsts, t)
+			}
+		}
+		if len(f.dsts) > 0 {
+			fans = append(fans, f)
+		}
+	}
+
+	// A stub, one lane per fan, and the arrow's shaft and head
+	g := edgeGap{width: max(1, len(fans)) + 3}
+	g.cells = make([][]gapCell, lines)
+	for y := range g.cells {
+		g.cells[y] = make([]gapCell, g.width)
+	}
+
+	for k, f := range fans {
+		blocked := !v.graph.IsDone(v.levels[col][f.src])
+		set := func(y, x, links int) {
+			g.cells[y][x].links |= links
+			g.cells[y][x].blocked = g.cells[y][x].blocked || blocked
+		}
+		lane := 1 + k
+		ys := f.src*graphSlotHeight + 1
+		for x := 0; x < lane; x++ {
+			set(ys, x, linkLeft|linkRight)
+		}
+		set(ys, lane, linkLeft)
+
+		top, bottom := ys, ys
+		for _, t := range f.dsts {
+			yt := t*graphSlotHeight + 2
+			top, bottom = min(top, yt), max(bottom, yt)
+			set(yt, lane, linkRight)
+			for x := lane + 1; x < g.width-1; x++ {
+				set(yt, x, linkLeft|linkRight)
+			}
+			set(yt, g.width-1, 0)
+			g.cells[yt][g.width-1].arrow = true
+		}
+		for y := top; y < bottom; y++ {
+			set(y, lane, linkDown)
+			set(y+1, lane, linkUp)
+		}
+	}
+	return g
+}
+
+// line renders one line of the gap, or blanks past its cells.
+func (g edgeGap) line(y int) string {
+	if y >= len(g.cells) {
+		return strings.Repeat(" ", g.width)
+	}
+	tags := GetTags()
+	var b strings.Builder
+	color := ""
+	for _, cell := range g.cells[y] {
+		glyph := " "
+		switch {
+		case cell.arrow:
+			glyph = "▶"
+		case cell.links != 0:
+			glyph = linkGlyphs[cell.links]
+		}
+		want := ""
+		if glyph != " " {
+			want = tags.Dim
+			if cell.blocked {
+				want = tags.Blocked
+			}
+		}
+		if want != color {
+			if color != "" {
+				b.WriteString("[-]")
+			}
+			if want != "" {
+				b.WriteString("[" + want + "]")
+			}
+			color = want
+		}
+		b.WriteString(glyph)
+	}
+	if color != "" {
+		b.WriteString("[-]")
+	}
+	return b.String()
+}
+
+// statusColor returns the theme tag for a node's box.
+func (v *GraphView) statusColor(id string) string {
+	tags := GetTags()
+	n := v.graph.Node(id)
+	if n == nil || n.Missing {
+		return tags.Dim
+	}
+	if n.Bead.Stuck {
+		return tags.StuckTag(n.Bead.StuckLevel, n.Bead.Snoozed)
+	}
+	switch n.Bead.Status.Canonical() {
+	case model.StatusClosed, model.StatusTombstone:
+		return tags.Done
+	case model.StatusBlocked:
+		return tags.Blocked
+	case model.StatusInProgress, model.StatusHooked:
+		return tags.InProgress
+	case model.StatusDeferred:
+		return tags.Warning
+	}
+	// Open beads still waiting on unfinished work are blocked in effect
+	for _, up := range n.Upstream {
+		if !v.graph.IsDone(up) {
+			return tags.Blocked
+		}
+	}
+	return tags.Info
+}
+
+// footer describes the selected bead and the key bindings.
+func (v *GraphView) footer() string {
+	tags := GetTags()
+	var b strings.Builder
+
+	if n := v.graph.Node(v.Selected()); n != nil {
+		b.WriteString("\n[::b]" + n.Bead.ID + "[::-] ")
+		if n.Missing {
+			b.WriteString("[" + tags.Dim + "]not in the current bead list[-]")
+		} else {
+			b.WriteString(tview.Escape(n.Bead.Title) + " [" + tags.Muted + "](" + string(n.Bead.Status) + ")[-]")
+			if n.Bead.Stuck {
+				b.WriteString(" " + stuckText(n.Bead.StuckReason, n.Bead.StuckLevel, n.Bead.StuckSince, n.Bead.Snoozed))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "[%s]h/l level  j/k bead  Enter re-center  +/- depth  Esc close[-]", tags.Dim)
+	return b.String()
+}
+
+// fitWidth truncates or pads s to exactly width runes.
+func fitWidth(s string, width int) string {
+	if utf8.RuneCountInString(s) > width {
+		runes := []rune(s)
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
@@ -17,17 +17,32 @@ func NewHelpOverlay() *tview.TextView {
   [aqua]Shift-Tab[-]     Focus previous panel
 
 [yellow::b]Actions[::-]
-  [aqua]Enter[-]         Drill down / select item
+  [aqua]Enter[-]         Convoy drill-down / details / expand event
+  [aqua]Tab[-]           In bead details: select a linked bead
+  [aqua]Esc[-]           Leave convoy drill-down
   [aqua]x[white] or [aqua]d[-]         Kill polecat / close bead
+  [aqua]n[-]             Nudge polecat with a message
+  [aqua]s[-]             Sling bead to a rig or polecat
+  [aqua]S[-]             Spawn a polecat in a rig
+  [aqua]a[-]             Acknowledge stuck item until it changes
+  [aqua]z[-]             Snooze stuck item for a while
   [aqua]r[-]             Manual refresh data
   [aqua]t[-]             Toggle auto-refresh on/off
 
 [yellow::b]Display[::-]
   [aqua]L[-]             Toggle events/logs panel
+  [aqua]M[-]             Toggle merge queue (refinery) panel
+  [aqua]A[-]             Toggle agents panel
+  [aqua]T[-]             Toggle beads table/tree view
+  [aqua]m[-]             Beads: all / ready / blocked with blockers
+  [aqua]Space[-]         Collapse/expand parent in tree
   [aqua]+[white]/[aqua]=[-]           Faster refresh (min 1s)
   [aqua]-[-]             Slower refresh (max 30s)
   [aqua]/[-]             Search beads by ID or title
   [aqua]f[-]             Filter beads by status
+  [aqua]R[-]             Focus a rig (rig picker)
+  [aqua]v[-]             Dependency graph for selected bead
+  [aqua]C[-]             Capacity: ready work vs idle polecats
 
 [yellow::b]General[::-]
   [aqua]?[-]             Show this help
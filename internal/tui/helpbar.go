@@ -47,13 +47,17 @@ func (h *HelpBar) UpdateForPanel(panel string) {
 	var shortcuts string
 	switch panel {
 	case "convoys":
-		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " View beads  " + key + "x" + end + " Close convoy  " + key + "h/l" + end + " Switch panel  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " Drill down  " + key + "Esc" + end + " Back  " + key + "x" + end + " Close convoy  " + key + "h/l" + end + " Switch panel  " + key + "a" + end + "/" + key + "z" + end + " Ack/Snooze  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
 	case "beads":
-		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " Details  " + key + "x" + end + " Close bead  " + key + "/" + end + " Search  " + key + "f" + end + " Filter  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " Details  " + key + "v" + end + " Graph  " + key + "T" + end + " Tree  " + key + "m" + end + " Mode  " + key + "x" + end + " Close bead  " + key + "s" + end + " Sling  " + key + "/" + end + " Search  " + key + "f" + end + " Filter  " + key + "a" + end + "/" + key + "z" + end + " Ack/Snooze  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
 	case "polecats":
-		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " Details  " + key + "x" + end + " Kill polecat  " + key + "h/l" + end + " Switch panel  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+		shortcuts = key + "j/k" + end + " Navigate  " + key + "Enter" + end + " Details  " + key + "x" + end + " Kill polecat  " + key + "n" + end + " Nudge  " + key + "S" + end + " Spawn  " + key + "h/l" + end + " Switch panel  " + key + "a" + end + "/" + key + "z" + end + " Ack/Snooze  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
 	case "events":
-		shortcuts = key + "j/k" + end + " Scroll  " + key + "h/l" + end + " Switch panel  " + key + "G" + end + " Bottom  " + key + "g" + end + " Top  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+		shortcuts = key + "j/k" + end + " Select  " + key + "Enter" + end + " Expand  " + key + "G" + end + " Follow  " + key + "h/l" + end + " Switch panel  " + key + "g" + end + " Top  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+	case "agents":
+		shortcuts = key + "j/k" + end + " Scroll  " + key + "h/l" + end + " Switch panel  " + key + "A" + end + " Hide agents  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
+	case "refinery":
+		shortcuts = key + "j/k" + end + " Scroll  " + key + "h/l" + end + " Switch panel  " + key + "M" + end + " Hide queue  " + key + "?" + end + " Help  " + key + "q" + end + " Quit"
 	default:
 		h.UpdateDefault()
 		return
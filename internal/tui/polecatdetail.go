@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/rivo/tview"
+)
+
+// PolecatDetail displays detailed info for a single polecat.
+type PolecatDetail struct {
+	view *tview.TextView
+}
+
+// NewPolecatDetail creates a new polecat detail view.
+func NewPolecatDetail() *PolecatDetail {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	return &PolecatDetail{view: view}
+}
+
+// Primitive returns the tview primitive.
+func (d *PolecatDetail) Primitive() tview.Primitive {
+	return d.view
+}
+
+// Update renders the polecat, its hooked bead, its timeline and its
+// worktree status. wt may be nil while git is still running or if
+// inspection failed.
+func (d *PolecatDetail) Update(pc *model.Polecat, timeline []model.TimelineEntry, wt *model.WorktreeStatus, wtErr error) {
+	tags := GetTags()
+	d.view.SetTitle(" " + pc.FullName() + " ")
+
+	var b strings.Builder
+	label := func(name, value string) {
+		fmt.Fprintf(&b, "[%s]%-14s[-] %s\n", tags.Muted, name, value)
+	}
+
+	label("State", string(pc.State))
+	label("Session", pc.SessionStatus())
+	if pc.SessionID != "" {
+		label("Session ID", pc.SessionID)
+	}
+	if pc.Windows > 0 {
+		label("Windows", fmt.Sprintf("%d", pc.Windows))
+	}
+	branch := pc.Branch
+	if branch == "" && wt != nil {
+		branch = wt.Branch
+	}
+	if branch != "" {
+		label("Branch", branch)
+	}
+	if pc.ClonePath != "" {
+		label("Clone path", pc.ClonePath)
+	}
+	if !pc.CreatedAt.IsZero() {
+		label("Created", detailTime(pc.CreatedAt, ""))
+	}
+	if !pc.LastActivity.IsZero() {
+		label("Last activity", detailTime(pc.LastActivity, pc.ActivityAgo()))
+	}
+	switch {
+	case pc.HookedBead != "":
+		label("Hooked", pc.HookedBead+" "+tview.Escape(pc.HookedTitle))
+	case pc.AssignedBead != "":
+		label("Assigned", pc.AssignedBead)
+	default:
+		label("Hooked", "["+tags.Dim+"]nothing[-]")
+	}
+	if pc.Stuck {
+		label("Stuck", stuckText(pc.StuckReason, pc.StuckLevel, pc.StuckSince, pc.Snoozed))
+	}
+
+	d.renderTimeline(&b, timeline)
+
+	fmt.Fprintf(&b, "\n[%s::b]Worktree[::-][-]\n", tags.Accent1)
+	switch {
+	case wtErr != nil:
+		fmt.Fprintf(&b, "[%s]%s[-]\n", tags.Error, wtErr.Error())
+	case wt == nil:
+		fmt.Fprintf(&b, "[%s]inspecting...[-]\n", tags.Dim)
+	default:
+		d.renderWorktree(&b, wt, label)
+	}
+
+	fmt.Fprintf(&b, "\n[%s]Esc to close[-]", tags.Dim)
+	d.view.SetText(b.String())
+}
+
+// renderTimeline writes the polecat's events since its spawn, with the
+// time elapsed between them.
+func (d *PolecatDetail) renderTimeline(b *strings.Builder, timeline []model.TimelineEntry) {
+	tags := GetTags()
+
+	fmt.Fprintf(b, "\n[%s::b]Timeline[::-][-]\n", tags.Accent1)
+	if len(timeline) == 0 {
+		fmt.Fprintf(b, "[%s]No events in the log[-]\n", tags.Dim)
+		return
+	}
+	for i := range timeline {
+		e := &timeline[i].Event
+		fmt.Fprintf(b, "[%s]%s %s[-] %s %s", tags.Dim, e.Timestamp.Local().Format("01-02"), e.TimeString(),
+			e.Icon(), tview.Escape(strings.TrimSpace(e.Actor+" "+e.Summary())))
+		if gap := timeline[i].GapString(); gap != "" {
+			fmt.Fprintf(b, " [%s]+%s[-]", tags.Dim, gap)
+		}
+		b.WriteString("\n")
+	}
+}
+
+// renderWorktree writes the git summary for a worktree.
+func (d *PolecatDetail) renderWorktree(b *strings.Builder, wt *model.WorktreeStatus, label func(name, value string)) {
+	tags := GetTags()
+
+	if wt.BaseBranch == "" {
+		label("Base", "["+tags.Warning+"]no default branch found[-]")
+	} else {
+		ahead := fmt.Sprintf("[%s]↑%d[-] [%s]↓%d[-] vs %s", tags.Success, wt.Ahead, tags.Warning, wt.Behind, wt.BaseBranch)
+		label("Commits", ahead)
+		label("Diff", wt.DiffStat())
+	}
+
+	if wt.IsDirty() {
+		label("Uncommitted", fmt.Sprintf("[%s]%d files[-]", tags.Warning, wt.Uncommitted))
+	} else {
+		label("Uncommitted", "clean")
+	}
+
+	if wt.LastCommit != "" {
+		label("Last commit", truncate(wt.LastCommit, 50)+" ["+tags.Dim+"]("+wt.LastCommitAgo()+" ago)[-]")
+	}
+
+	if wt.HasConflicts {
+		label("Conflicts", "["+tags.Error+"]conflicts with "+wt.BaseBranch+"[-]")
+	} else if wt.BaseBranch != "" {
+		label("Conflicts", "["+tags.Success+"]none[-]")
+	}
+}
@@ -60,16 +60,16 @@ func (p *PolecatsPanel) Update(polecats []model.Polecat) {
 		iconColor := ""
 		if pc.Stuck {
 			icon = "⚠"
-			iconColor = "[" + tags.Error + "]"
+			iconColor = "[" + tags.StuckTag(pc.StuckLevel, pc.Snoozed) + "]"
 		} else {
-			switch pc.State {
-			case "working":
+			switch pc.State.Canonical() {
+			case model.PolecatWorking:
 				// Use spinner for working state
 				icon = spinnerFrames[p.spinnerIdx]
 				iconColor = "[" + tags.Working + "]"
-			case "done":
+			case model.PolecatDone:
 				iconColor = "[" + tags.Done + "]"
-			case "idle":
+			case model.PolecatIdle:
 				iconColor = "[" + tags.Idle + "]"
 			}
 		}
@@ -101,12 +101,12 @@ func (p *PolecatsPanel) Update(polecats []model.Polecat) {
 		if work := pc.WorkDescription(); work != "" {
 			secondary += "[" + tags.Accent1 + "]" + work + "[-]"
 		} else {
-			secondary += "[" + tags.Muted + "]" + pc.State + "[-]"
+			secondary += "[" + tags.Muted + "]" + string(pc.State) + "[-]"
 		}
 
 		// Add stuck reason if stuck
 		if pc.Stuck {
-			secondary += " [" + tags.Error + "]" + pc.StuckReason + "[-]"
+			secondary += " " + stuckText(pc.StuckReason, pc.StuckLevel, pc.StuckSince, pc.Snoozed)
 		}
 
 		idx := i
@@ -138,12 +138,12 @@ func (p *PolecatsPanel) UpdateWithSpinner(polecats []model.Polecat) {
 func (p *PolecatsPanel) CountByState() string {
 	working, done, idle := 0, 0, 0
 	for _, pc := range p.polecats {
-		switch pc.State {
-		case "working":
+		switch pc.State.Canonical() {
+		case model.PolecatWorking:
 			working++
-		case "done":
+		case model.PolecatDone:
 			done++
-		case "idle":
+		case model.PolecatIdle:
 			idle++
 		}
 	}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/davidsenack/gastop/internal/refinery"
+	"github.com/rivo/tview"
+)
+
+// maxMergedPerRig limits how many landed merges are listed per rig.
+const maxMergedPerRig = 3
+
+// RefineryPanel displays the merge queue of each rig.
+type RefineryPanel struct {
+	view   *tview.TextView
+	queues []refinery.Queue
+}
+
+// NewRefineryPanel creates a new refinery panel.
+func NewRefineryPanel() *RefineryPanel {
+	theme := GetTheme()
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetTextColor(theme.Foreground)
+
+	view.SetBorder(true).
+		SetTitle(" REFINERY ").
+		SetBorderColor(theme.BorderColor).
+		SetTitleColor(theme.TitleColor)
+
+	return &RefineryPanel{view: view}
+}
+
+// Primitive returns the tview primitive.
+func (p *RefineryPanel) Primitive() tview.Primitive {
+	return p.view
+}
+
+// Update updates the panel with new merge queue data.
+func (p *RefineryPanel) Update(queues []refinery.Queue) {
+	tags := GetTags()
+	p.queues = queues
+
+	var b strings.Builder
+	for _, q := range queues {
+		counts := q.Counts()
+		fmt.Fprintf(&b, "[%s::b]%s[::-][-] [%s]⏳%d ⚙%d[-] [%s]✓%d[-] [%s]✗%d[-]\n",
+			tags.Accent1, q.Rig,
+			tags.Muted, counts[model.MergeQueued], counts[model.MergeInFlight],
+			tags.Success, counts[model.MergeMerged],
+			tags.Error, counts[model.MergeFailed])
+
+		merged := 0
+		for _, mr := range q.Requests {
+			if mr.State == model.MergeMerged {
+				merged++
+				if merged > maxMergedPerRig {
+					continue
+				}
+			}
+			b.WriteString(p.formatRequest(mr) + "\n")
+		}
+	}
+
+	if len(queues) == 0 {
+		fmt.Fprintf(&b, "[%s]No merge activity[-]\n", tags.Muted)
+	}
+
+	if alerts := p.AlertCount(); alerts > 0 {
+		p.view.SetTitle(fmt.Sprintf(" REFINERY [%s]⚠ %d failing[-] ", tags.Error, alerts))
+	} else {
+		p.view.SetTitle(" REFINERY ")
+	}
+	p.view.SetText(b.String())
+}
+
+// formatRequest renders a single merge request line.
+func (p *RefineryPanel) formatRequest(mr *model.MergeRequest) string {
+	tags := GetTags()
+
+	var color string
+	switch mr.State {
+	case model.MergeInFlight:
+		color = tags.Working
+	case model.MergeMerged:
+		color = tags.Done
+	case model.MergeFailed:
+		color = tags.Error
+	default:
+		color = tags.Muted
+	}
+
+	name := mr.Branch
+	if name == "" {
+		name = mr.Bead
+	}
+	line := fmt.Sprintf("  [%s]%s[-] %s", color, mr.StateIcon(), truncate(name, 28))
+	if mr.Bead != "" && mr.Bead != name {
+		line += " [" + tags.Accent1 + "]" + mr.Bead + "[-]"
+	}
+	if age := mr.TimeInQueueString(); age != "" {
+		line += " [" + tags.Dim + "]" + age + "[-]"
+	}
+	if mr.Failures > 0 && mr.State != model.MergeMerged {
+		line += fmt.Sprintf(" [%s]%d× %s[-]", tags.Error, mr.Failures, truncate(mr.FailureReason, 40))
+	}
+	if mr.State != model.MergeMerged && mr.RepeatedlyFailing() {
+		line += " [" + tags.Error + "::b]⚠ REPEATED[::-][-]"
+	}
+	return line
+}
+
+// AlertCount returns the number of repeatedly failing merges.
+func (p *RefineryPanel) AlertCount() int {
+	n := 0
+	for i := range p.queues {
+		n += len(p.queues[i].Alerts())
+	}
+	return n
+}
+
+// ScrollDown scrolls the view down one line.
+func (p *RefineryPanel) ScrollDown() {
+	row, col := p.view.GetScrollOffset()
+	p.view.ScrollTo(row+1, col)
+}
+
+// ScrollUp scrolls the view up one line.
+func (p *RefineryPanel) ScrollUp() {
+	row, col := p.view.GetScrollOffset()
+	if row > 0 {
+		p.view.ScrollTo(row-1, col)
+	}
+}
+
+// ScrollToTop scrolls to the top of the view.
+func (p *RefineryPanel) ScrollToTop() {
+	p.view.ScrollToBeginning()
+}
+
+// ScrollToBottom scrolls to the bottom of the view.
+func (p *RefineryPanel) ScrollToBottom() {
+	p.view.ScrollToEnd()
+}
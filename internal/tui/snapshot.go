@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"github.com/davidsenack/gastop/internal/engine"
+)
+
+// applySnapshot stores the snapshot and updates the panels in one draw.
+func (a *App) applySnapshot(s engine.Snapshot) {
+	a.mu.Lock()
+	if s.HasPolecats {
+		a.polecatData = s.Polecats
+	}
+	if s.HasBeads {
+		a.beadData = s.Beads
+	}
+	if s.HasConvoys {
+		a.convoyData = s.Convoys
+	}
+	if s.HasEvents {
+		a.eventData = s.Events
+	}
+	filter := a.beadStatusFilter
+	allBeads := a.beadMode == beadModeAll // Otherwise refreshBeadQueue fills the panel
+	a.mu.Unlock()
+
+	filtered := a.filterBeadsByStatus(s.Beads, filter)
+	recent := s.Events
+	if len(recent) > a.config.LogLines {
+		recent = recent[len(recent)-a.config.LogLines:]
+	}
+
+	a.app.QueueUpdateDraw(func() {
+		if a.inConvoyFocus() {
+			a.polecats.AdvanceSpinner()
+			a.showConvoyFocus()
+		} else {
+			if s.HasPolecats {
+				a.polecats.UpdateWithSpinner(s.Polecats)
+			}
+			if s.HasBeads && allBeads {
+				a.beads.Update(filtered)
+				if filter != "" {
+					a.beads.SetTitle(beadsTitle(beadModeAll, filter))
+				}
+			}
+		}
+		if s.HasConvoys {
+			a.convoys.Update(s.Convoys)
+		}
+		if s.HasEvents {
+			a.events.Update(recent)
+		}
+	})
+}
+
+// setError records an error for the status bar.
+func (a *App) setError(msg string) {
+	a.mu.Lock()
+	a.lastError = msg
+	a.mu.Unlock()
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/davidsenack/gastop/internal/stuck"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// snoozeDurations are the choices offered by the snooze picker.
+var snoozeDurations = []struct {
+	label string
+	d     time.Duration
+}{
+	{"15 minutes", 15 * time.Minute},
+	{"1 hour", time.Hour},
+	{"4 hours", 4 * time.Hour},
+	{"1 day", 24 * time.Hour},
+}
+
+// snoozeTarget is the item selected in the focused panel, for ack and
+// snooze.
+type snoozeTarget struct {
+	entity  string
+	id      string
+	stuck   bool
+	snoozed bool
+	snooze  func(s *stuck.Snoozes, d time.Duration) error
+}
+
+// selectedSnoozeTarget returns the selected bead, polecat or convoy.
+func (a *App) selectedSnoozeTarget() *snoozeTarget {
+	switch a.app.GetFocus() {
+	case a.beads.Primitive():
+		if b := a.beads.Selected(); b != nil {
+			bead := *b
+			return &snoozeTarget{stuck.EntityBead, b.ID, b.Stuck, b.Snoozed,
+				func(s *stuck.Snoozes, d time.Duration) error { return s.SnoozeBead(&bead, d) }}
+		}
+	case a.polecats.Primitive():
+		if p := a.polecats.Selected(); p != nil {
+			pc := *p
+			return &snoozeTarget{stuck.EntityPolecat, p.FullName(), p.Stuck, p.Snoozed,
+				func(s *stuck.Snoozes, d time.Duration) error { return s.SnoozePolecat(&pc, d) }}
+		}
+	case a.convoys.Primitive():
+		if c := a.convoys.Selected(); c != nil {
+			convoy := *c
+			return &snoozeTarget{stuck.EntityConvoy, c.ID, c.Stuck, c.Snoozed,
+				func(s *stuck.Snoozes, d time.Duration) error { return s.SnoozeConvoy(&convoy, d) }}
+		}
+	}
+	return nil
+}
+
+// ackSelected acknowledges the selected stuck item until it changes, or
+// lifts an existing ack or snooze.
+func (a *App) ackSelected() {
+	t := a.selectedSnoozeTarget()
+	if t == nil {
+		return
+	}
+	if t.snoozed {
+		a.unsnooze(t)
+		return
+	}
+	if !t.stuck {
+		a.showMessage(t.id + " is not stuck")
+		return
+	}
+	a.applySnooze(t, 0)
+}
+
+// showSnoozePicker offers snooze durations for the selected stuck item.
+func (a *App) showSnoozePicker() {
+	t := a.selectedSnoozeTarget()
+	if t == nil {
+		return
+	}
+	if !t.stuck && !t.snoozed {
+		a.showMessage(t.id + " is not stuck")
+		return
+	}
+
+	list := tview.NewList()
+	for i, choice := range snoozeDurations {
+		d := choice.d
+		list.AddItem(choice.label, "", rune('1'+i), func() {
+			a.closeOverlay()
+			a.applySnooze(t, d)
+		})
+	}
+	list.AddItem("Until it changes", "Same as a (acknowledge)", 'c', func() {
+		a.closeOverlay()
+		a.applySnooze(t, 0)
+	})
+	height := len(snoozeDurations) + 3
+	if t.snoozed {
+		list.AddItem("Unsnooze", a.snoozeDescription(t), 'u', func() {
+			a.closeOverlay()
+			a.unsnooze(t)
+		})
+		height += 2
+	}
+	list.ShowSecondaryText(t.snoozed)
+
+	list.SetBorder(true).SetTitle(" Snooze " + t.id + " ")
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeOverlay()
+			return nil
+		}
+		return event
+	})
+
+	a.showOverlay(list, 44, height+3)
+}
+
+// snoozeDescription describes the current snooze of an item.
+func (a *App) snoozeDescription(t *snoozeTarget) string {
+	if sn, ok := a.snoozes.Get(t.entity, t.id); ok {
+		return "Snoozed " + sn.Describe(time.Now())
+	}
+	return ""
+}
+
+// applySnooze snoozes an item for d (0 = until it changes) and refreshes
+// so panels and alerts pick it up.
+func (a *App) applySnooze(t *snoozeTarget, d time.Duration) {
+	if err := t.snooze(a.snoozes, d); err != nil {
+		a.showMessage("Failed to save snooze: " + err.Error())
+		return
+	}
+	go a.refresh()
+}
+
+// unsnooze lifts an ack or snooze.
+func (a *App) unsnooze(t *snoozeTarget) {
+	if err := a.snoozes.Remove(t.entity, t.id); err != nil {
+		a.showMessage("Failed to save snooze: " + err.Error())
+		return
+	}
+	go a.refresh()
+}
@@ -13,6 +13,7 @@ type StatusBar struct {
 	view        *tview.TextView
 	refreshTick int
 	lastRefresh time.Time
+	alert       string
 }
 
 // NewStatusBar creates a new status bar.
@@ -62,6 +63,10 @@ func (s *StatusBar) Update(townName, rigName, interval string, connected, stale
 	}
 	line += fmt.Sprintf(" │ ↻ %s %s", interval, status)
 
+	if s.alert != "" {
+		line += fmt.Sprintf(" │ ["+tags.Error+"::b]⚠ %s[::-][-]", s.alert)
+	}
+
 	if lastError != "" {
 		line += fmt.Sprintf(" │ [" + tags.Error + "]%s[-]", truncate(lastError, 30))
 	}
@@ -72,6 +77,11 @@ func (s *StatusBar) Update(townName, rigName, interval string, connected, stale
 	s.view.SetText(line)
 }
 
+// SetAlert sets a persistent alert shown until cleared with "".
+func (s *StatusBar) SetAlert(alert string) {
+	s.alert = alert
+}
+
 // SetBackgroundColor sets the background color.
 func (s *StatusBar) SetBackgroundColor(color tcell.Color) {
 	s.view.SetBackgroundColor(color)
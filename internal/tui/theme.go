@@ -1,6 +1,11 @@
 package tui
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"time"
+
+	"github.com/davidsenack/gastop/internal/model"
+	"github.com/gdamore/tcell/v2"
+)
 
 // Theme defines the color scheme for gastop.
 // Using a cohesive palette inspired by terminal aesthetics.
@@ -123,3 +128,40 @@ func GetTheme() *Theme {
 func GetTags() *ThemeTags {
 	return currentTags
 }
+
+// StuckColor returns the color for a stuck severity tier: amber for
+// warnings, red for critical. Snoozed items are muted.
+func (t *Theme) StuckColor(level model.Severity, snoozed bool) tcell.Color {
+	if snoozed {
+		return t.Muted
+	}
+	if level == model.SeverityCritical {
+		return t.Stuck
+	}
+	return t.Warning
+}
+
+// StuckTag returns the color tag for a stuck severity tier.
+func (t *ThemeTags) StuckTag(level model.Severity, snoozed bool) string {
+	if snoozed {
+		return t.Dim
+	}
+	if level == model.SeverityCritical {
+		return t.Stuck
+	}
+	return t.Warning
+}
+
+// stuckText renders a stuck reason in its tier color, followed by how long
+// the item has been stuck when known.
+func stuckText(reason string, level model.Severity, since time.Time, snoozed bool) string {
+	tags := GetTags()
+	s := "[" + tags.StuckTag(level, snoozed) + "]" + reason + "[-]"
+	if snoozed {
+		return s + " [" + tags.Dim + "](snoozed)[-]"
+	}
+	if d := model.StuckFor(since); d != "" {
+		s += " [" + tags.Dim + "](stuck " + d + ")[-]"
+	}
+	return s
+}